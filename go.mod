@@ -1,9 +1,10 @@
 module github.com/jpcedenog/gointercept
 
-go 1.14
+go 1.18
 
 require (
-	github.com/aws/aws-lambda-go v1.17.0
-	github.com/jchannon/negotiator v0.0.0-20160316153602-775415799f89 // indirect
+	github.com/aws/aws-lambda-go v1.38.0
 	github.com/qri-io/jsonschema v0.2.0
 )
+
+require github.com/qri-io/jsonpointer v0.1.1 // indirect
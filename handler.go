@@ -17,11 +17,15 @@ type LambdaHandler func(context.Context, interface{}) (interface{}, error)
 type ErrorHandler func(context.Context, interface{}, error) (interface{}, error)
 
 // Interceptor contains the three potential handlers that can be applied during the Lambda function
-// lifecycle. That is, a handler to be executed before, after, an on error of the Lambda function
+// lifecycle. That is, a handler to be executed before, after, an on error of the Lambda function.
+//
+// Optionally, Around receives the rest of the chain (the following interceptors and the Lambda function) and returns
+// the handler executed in its place. This allows an interceptor to skip, repeat, or guard the execution of the chain
 type Interceptor struct {
 	Before  LambdaHandler
 	After   LambdaHandler
 	OnError ErrorHandler
+	Around  func(next LambdaHandler) LambdaHandler
 }
 
 // The InterceptedHandler type wraps a LambdaHandler so interceptors can be applied to it
@@ -43,12 +47,23 @@ func (a *InterceptedHandler) With(adapters ...Interceptor) LambdaHandler {
 	return handler
 }
 
-// This function converts the given Lambda function into an InterceptedHandler
+// This function converts the given Lambda function into an InterceptedHandler. LambdaHandler instances (e.g. handlers
+// already wrapped with interceptors) are used as they are, so the payload is passed to them unchanged
 func This(handler interface{}) *InterceptedHandler {
+	switch h := handler.(type) {
+	case LambdaHandler:
+		return &InterceptedHandler{handler: h}
+	case func(context.Context, interface{}) (interface{}, error):
+		return &InterceptedHandler{handler: h}
+	}
 	return &InterceptedHandler{handler: newHandler(handler)}
 }
 
 func (interceptor Interceptor) handle(handler LambdaHandler) LambdaHandler {
+	if interceptor.Around != nil {
+		handler = interceptor.Around(handler)
+	}
+
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		response := request
 		var err error
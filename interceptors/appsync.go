@@ -0,0 +1,167 @@
+package interceptors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+)
+
+// AppSyncResolverEvent represents the event sent by AppSync to direct Lambda resolvers. Its body, as seen by
+// interceptors such as ParseBody and ValidateBodyJSONSchema, is the GraphQL arguments of the field being resolved
+type AppSyncResolverEvent struct {
+	Arguments json.RawMessage        `json:"arguments"`
+	Identity  *AppSyncIdentity       `json:"identity"`
+	Source    json.RawMessage        `json:"source"`
+	Request   AppSyncRequest         `json:"request"`
+	Info      AppSyncInfo            `json:"info"`
+	Prev      json.RawMessage        `json:"prev"`
+	Stash     map[string]interface{} `json:"stash"`
+}
+
+// GetBody returns the GraphQL arguments of the event
+func (e AppSyncResolverEvent) GetBody() (string, error) {
+	if len(e.Arguments) == 0 {
+		return "{}", nil
+	}
+
+	return string(e.Arguments), nil
+}
+
+// AppSyncIdentity contains information about the caller. The fields populated depend on the authorization mode
+// (Cognito User Pools, OIDC, IAM, or Lambda)
+type AppSyncIdentity struct {
+	Sub                 string                 `json:"sub"`
+	Issuer              string                 `json:"issuer"`
+	Username            string                 `json:"username"`
+	Claims              map[string]interface{} `json:"claims"`
+	Groups              []string               `json:"groups"`
+	SourceIP            []string               `json:"sourceIp"`
+	DefaultAuthStrategy string                 `json:"defaultAuthStrategy"`
+	AccountID           string                 `json:"accountId"`
+	UserARN             string                 `json:"userArn"`
+	ResolverContext     map[string]interface{} `json:"resolverContext"`
+}
+
+// AppSyncRequest contains the HTTP headers of the GraphQL request
+type AppSyncRequest struct {
+	Headers map[string]string `json:"headers"`
+}
+
+// AppSyncInfo describes the GraphQL field being resolved
+type AppSyncInfo struct {
+	FieldName        string                 `json:"fieldName"`
+	ParentTypeName   string                 `json:"parentTypeName"`
+	Variables        map[string]interface{} `json:"variables"`
+	SelectionSetList []string               `json:"selectionSetList"`
+}
+
+// AppSyncError is an error with a custom GraphQL error type. Errors of other types raised during the resolution of a
+// field are given a type based on their HTTP status code (HTTPError) or the 'InternalError' type
+type AppSyncError struct {
+	ErrorType    string
+	ErrorMessage string
+}
+
+func (e *AppSyncError) Error() string {
+	return e.ErrorMessage
+}
+
+// AppSyncBatchResult represents the result of a single item of a batch resolver invocation
+type AppSyncBatchResult struct {
+	Data         interface{} `json:"data"`
+	ErrorType    string      `json:"errorType,omitempty"`
+	ErrorMessage string      `json:"errorMessage,omitempty"`
+}
+
+type appSyncEventKey struct{}
+
+// GetAppSyncEvent returns the AppSync resolver event added to the context by HandleAppSyncResolver, if any. It gives
+// access to the identity, source, and info of the event after its arguments have been parsed
+func GetAppSyncEvent(ctx context.Context) (AppSyncResolverEvent, bool) {
+	event, ok := ctx.Value(appSyncEventKey{}).(AppSyncResolverEvent)
+	return event, ok
+}
+
+// AppSyncClaims returns the identity claims of the caller of the AppSync resolver event in the context, if any
+func AppSyncClaims(ctx context.Context) map[string]interface{} {
+	if event, ok := GetAppSyncEvent(ctx); ok && event.Identity != nil {
+		return event.Identity.Claims
+	}
+
+	return nil
+}
+
+// HandleAppSyncResolver decodes AppSync direct Lambda resolver events, single or batched (BatchInvoke), into
+// AppSyncResolverEvent instances. Batched events are resolved one at a time by the rest of the chain. The event being
+// resolved is available through the context (see GetAppSyncEvent and AppSyncClaims) and its arguments can be parsed
+// and validated with ParseBody and ValidateBodyJSONSchema.
+//
+// Errors are reported with the 'errorType' and 'errorMessage' expected by AppSync: as the Lambda function's error for
+// single events, and as an AppSyncBatchResult for each item of batched events
+func HandleAppSyncResolver() gointercept.Interceptor {
+	return gointercept.Interceptor{
+		Around: func(next gointercept.LambdaHandler) gointercept.LambdaHandler {
+			return func(ctx context.Context, payload interface{}) (interface{}, error) {
+				payloadBytes, err := internal.GetBytes(payload)
+				if err != nil {
+					return payload, err
+				}
+
+				if !bytes.HasPrefix(bytes.TrimSpace(payloadBytes), []byte("[")) {
+					var event AppSyncResolverEvent
+					if err := json.Unmarshal(payloadBytes, &event); err != nil {
+						return payload, fmt.Errorf("payload is not an AppSync resolver event - %w", err)
+					}
+
+					response, err := next(context.WithValue(ctx, appSyncEventKey{}, event), event)
+					if err != nil {
+						errorType, errorMessage := formatAppSyncError(err)
+						return nil, messages.InvokeResponse_Error{Type: errorType, Message: errorMessage}
+					}
+
+					return response, nil
+				}
+
+				var batch []AppSyncResolverEvent
+				if err := json.Unmarshal(payloadBytes, &batch); err != nil {
+					return payload, fmt.Errorf("payload is not a batch of AppSync resolver events - %w", err)
+				}
+
+				results := make([]AppSyncBatchResult, len(batch))
+				for i, event := range batch {
+					response, err := next(context.WithValue(ctx, appSyncEventKey{}, event), event)
+					if err != nil {
+						results[i].ErrorType, results[i].ErrorMessage = formatAppSyncError(err)
+						continue
+					}
+					results[i].Data = response
+				}
+
+				return results, nil
+			}
+		},
+	}
+}
+
+func formatAppSyncError(err error) (string, string) {
+	var appSyncError *AppSyncError
+	if errors.As(err, &appSyncError) {
+		return appSyncError.ErrorType, appSyncError.ErrorMessage
+	}
+
+	var httpError *HTTPError
+	if errors.As(err, &httpError) {
+		var validationError *ValidationError
+		if errors.As(httpError, &validationError) {
+			return httpErrorType(httpError.StatusCode), validationError.Error()
+		}
+		return httpErrorType(httpError.StatusCode), httpError.StatusText
+	}
+
+	return "InternalError", err.Error()
+}
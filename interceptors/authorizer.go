@@ -0,0 +1,148 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"strings"
+)
+
+// AuthorizerDecision represents the outcome of a Lambda authorizer. Authorizer functions wrapped with
+// CreateAuthorizerResponse or CreateSimpleAuthorizerResponse return it instead of hand-built policy documents
+type AuthorizerDecision struct {
+	Allow       bool                   `json:"allow"`
+	PrincipalID string                 `json:"principalId"`
+	Context     map[string]interface{} `json:"context,omitempty"`
+}
+
+// AuthorizerScope specifies the resources covered by the policy generated by CreateAuthorizerResponse. Wider scopes
+// allow API Gateway to reuse a cached policy for other methods of the API
+type AuthorizerScope int
+
+const (
+	// MethodScope limits the policy to the method being invoked (the request's methodArn)
+	MethodScope AuthorizerScope = iota
+	// StageScope extends the policy to every method of the invoked stage
+	StageScope
+	// APIScope extends the policy to every method of every stage of the invoked API
+	APIScope
+)
+
+type authorizerRequest struct {
+	MethodArn string `json:"methodArn"`
+}
+
+// CreateAuthorizerResponse turns the AuthorizerDecision returned by a REST API (v1) token or request authorizer into
+// an APIGatewayCustomAuthorizerResponse. The policy allows or denies 'execute-api:Invoke' on the resources specified
+// by the given scope. Requests without a valid methodArn fail before the authorizer function runs, so no policy is
+// generated for them.
+//
+// Errors are passed on as they are. Note that API Gateway answers with a 401 status code if the error's message is
+// 'Unauthorized', and with a 500 status code otherwise
+func CreateAuthorizerResponse(scope AuthorizerScope) gointercept.Interceptor {
+	return gointercept.Interceptor{
+		Around: func(next gointercept.LambdaHandler) gointercept.LambdaHandler {
+			return func(ctx context.Context, payload interface{}) (interface{}, error) {
+				var request authorizerRequest
+				if err := internal.Decode(payload, &request); err != nil {
+					return payload, fmt.Errorf("payload is not an authorizer request - %w", err)
+				}
+				resource, err := scopeResource(request.MethodArn, scope)
+				if err != nil {
+					return payload, err
+				}
+
+				response, err := next(ctx, payload)
+				if err != nil {
+					return response, err
+				}
+
+				decision, err := getAuthorizerDecision(response)
+				if err != nil {
+					return response, err
+				}
+
+				effect := "Deny"
+				if decision.Allow {
+					effect = "Allow"
+				}
+
+				return events.APIGatewayCustomAuthorizerResponse{
+					PrincipalID: decision.PrincipalID,
+					PolicyDocument: events.APIGatewayCustomAuthorizerPolicy{
+						Version: "2012-10-17",
+						Statement: []events.IAMPolicyStatement{{
+							Action:   []string{"execute-api:Invoke"},
+							Effect:   effect,
+							Resource: []string{resource},
+						}},
+					},
+					Context: decision.Context,
+				}, nil
+			}
+		},
+	}
+}
+
+// CreateSimpleAuthorizerResponse turns the AuthorizerDecision returned by an HTTP API (v2) authorizer into an
+// APIGatewayV2CustomAuthorizerSimpleResponse. The decision's principal is ignored since simple responses don't have one
+func CreateSimpleAuthorizerResponse() gointercept.Interceptor {
+	return gointercept.Interceptor{
+		After: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			decision, err := getAuthorizerDecision(payload)
+			if err != nil {
+				return payload, err
+			}
+
+			return events.APIGatewayV2CustomAuthorizerSimpleResponse{
+				IsAuthorized: decision.Allow,
+				Context:      decision.Context,
+			}, nil
+		},
+	}
+}
+
+func getAuthorizerDecision(response interface{}) (AuthorizerDecision, error) {
+	switch decision := response.(type) {
+	case AuthorizerDecision:
+		return decision, nil
+	case *AuthorizerDecision:
+		if decision != nil {
+			return *decision, nil
+		}
+	}
+
+	var decision AuthorizerDecision
+	if response == nil {
+		return decision, fmt.Errorf("authorizer returned no decision")
+	}
+	if err := internal.Decode(response, &decision); err != nil {
+		return decision, fmt.Errorf("authorizer response is not a decision - %w", err)
+	}
+
+	return decision, nil
+}
+
+// scopeResource widens the given method ARN (arn:aws:execute-api:{region}:{account}:{api}/{stage}/{method}/{path})
+// according to the given scope, or returns an error if the ARN is empty or malformed
+func scopeResource(methodArn string, scope AuthorizerScope) (string, error) {
+	fields := strings.SplitN(methodArn, ":", 6)
+	if len(fields) != 6 || fields[0] != "arn" || fields[2] != "execute-api" {
+		return "", fmt.Errorf("invalid methodArn %q", methodArn)
+	}
+	parts := strings.SplitN(methodArn, "/", 4)
+	if len(parts) < 3 || parts[1] == "" || parts[2] == "" || strings.HasSuffix(parts[0], ":") {
+		return "", fmt.Errorf("invalid methodArn %q", methodArn)
+	}
+
+	switch scope {
+	case APIScope:
+		return parts[0] + "/*", nil
+	case StageScope:
+		return parts[0] + "/" + parts[1] + "/*", nil
+	}
+
+	return methodArn, nil
+}
@@ -0,0 +1,48 @@
+package interceptors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ItemError represents the failure of a single item (e.g. an S3 object) processed as part of a batch. ID identifies
+// the item within the batch
+type ItemError struct {
+	ID  string
+	Err error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("%s: %s", e.ID, e.Err.Error())
+}
+
+// Unwrap returns the error raised while processing the item
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError aggregates the errors raised while processing the items of a batch. Items that are not listed succeeded
+type BatchError struct {
+	Errors []*ItemError
+}
+
+func (e *BatchError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, itemError := range e.Errors {
+		messages[i] = itemError.Error()
+	}
+
+	return fmt.Sprintf("%d item(s) failed: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+func (e *BatchError) add(id string, err error) {
+	e.Errors = append(e.Errors, &ItemError{ID: id, Err: err})
+}
+
+func (e *BatchError) errorOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e
+}
@@ -0,0 +1,138 @@
+package interceptors
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// bindingName returns the name a struct field is bound to: the name in the given tag, the name in its 'json' tag, or
+// the field name. Unexported fields and fields tagged with '-' are not bound
+func bindingName(field reflect.StructField, tag string) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	for _, t := range []string{tag, "json"} {
+		if value, ok := field.Tag.Lookup(t); ok {
+			name := strings.Split(value, ",")[0]
+			if name == "-" {
+				return "", false
+			}
+			if name != "" {
+				return name, true
+			}
+		}
+	}
+
+	return field.Name, true
+}
+
+// bindValues stores the given values, keyed by name, in the fields of the struct pointed to by target. Keys that don't
+// match any field are returned
+func bindValues(values map[string][]string, tag string, target interface{}) ([]string, error) {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't bind values to %T - target must be a pointer to a struct", target)
+	}
+	value = value.Elem()
+
+	matched := make(map[string]bool, len(values))
+	for i := 0; i < value.NumField(); i++ {
+		name, ok := bindingName(value.Type().Field(i), tag)
+		if !ok {
+			continue
+		}
+		v, ok := values[name]
+		if !ok || len(v) == 0 {
+			continue
+		}
+		matched[name] = true
+		if err := setValues(value.Field(i), v); err != nil {
+			return nil, fmt.Errorf("invalid value for %s - %w", name, err)
+		}
+	}
+
+	var unknown []string
+	for name := range values {
+		if !matched[name] {
+			unknown = append(unknown, name)
+		}
+	}
+
+	return unknown, nil
+}
+
+// setValues converts the given string values to the type of the given field and stores them in it. Slices receive
+// all the values, other types the first one
+func setValues(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 &&
+		!reflect.PtrTo(field.Type()).Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, v := range values {
+			if err := setValue(slice.Index(i), v); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	return setValue(field, values[0])
+}
+
+// setValue converts the given string value to the type of the given field and stores it in it
+func setValue(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setValue(ptr.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+		field.SetBytes([]byte(value))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+
+	return nil
+}
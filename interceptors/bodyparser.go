@@ -0,0 +1,137 @@
+package interceptors
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// BodyDecoder decodes a request body into the value pointed to by target. The parameters of the request's media
+// type (e.g. the multipart boundary) are provided as well. allowUnknownFields is the value given to ParseBody
+type BodyDecoder func(body string, params map[string]string, target interface{}, allowUnknownFields bool) error
+
+const (
+	defaultMaxMultipartPartSize  = 1 << 20
+	defaultMaxMultipartTotalSize = 6 << 20
+)
+
+type bodyParser struct {
+	decoders              map[string]BodyDecoder
+	maxMultipartPartSize  int64
+	maxMultipartTotalSize int64
+}
+
+// ParseBodyOption represents a configuration option for the ParseBody interceptor
+type ParseBodyOption func(*bodyParser)
+
+// WithBodyDecoder registers the decoder used for the given media type (e.g. 'application/yaml'), replacing the
+// default one if any. Media types are case-insensitive
+func WithBodyDecoder(mediaType string, decoder BodyDecoder) ParseBodyOption {
+	return func(p *bodyParser) {
+		p.decoders[strings.ToLower(mediaType)] = decoder
+	}
+}
+
+// WithMultipartLimits sets the maximum size of each part, and of the whole body, of the multipart forms parsed by the
+// default 'multipart/form-data' decoder. Larger forms are rejected with a 413 HTTPError. Zero means no limit. The
+// defaults are 1 MiB per part and 6 MiB per body (the maximum payload of synchronous Lambda invocations)
+func WithMultipartLimits(maxPartSize, maxTotalSize int64) ParseBodyOption {
+	return func(p *bodyParser) {
+		p.maxMultipartPartSize = maxPartSize
+		p.maxMultipartTotalSize = maxTotalSize
+	}
+}
+
+func newBodyParser(options []ParseBodyOption) *bodyParser {
+	parser := &bodyParser{
+		maxMultipartPartSize:  defaultMaxMultipartPartSize,
+		maxMultipartTotalSize: defaultMaxMultipartTotalSize,
+	}
+	parser.decoders = map[string]BodyDecoder{
+		"application/json":                  decodeJSONBody,
+		"application/x-www-form-urlencoded": decodeFormBody,
+		"multipart/form-data":               parser.decodeMultipartBody,
+		"application/xml":                   decodeXMLBody,
+		"text/xml":                          decodeXMLBody,
+	}
+	for _, opt := range options {
+		opt(parser)
+	}
+
+	return parser
+}
+
+// decoder returns the decoder for the given Content-Type header and its media type parameters. JSON is assumed when
+// no Content-Type is given, and for media types with the '+json' suffix (e.g. 'application/vnd.api+json')
+func (p *bodyParser) decoder(contentType string) (BodyDecoder, map[string]string, error) {
+	if contentType == "" {
+		return p.decoders["application/json"], nil, nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid Content-Type %q - %w", contentType, err)
+	}
+	if decoder, ok := p.decoders[mediaType]; ok {
+		return decoder, params, nil
+	}
+	if strings.HasSuffix(mediaType, "+json") {
+		return p.decoders["application/json"], params, nil
+	}
+	if strings.HasSuffix(mediaType, "+xml") {
+		return p.decoders["application/xml"], params, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported Content-Type %q", mediaType)
+}
+
+func decodeJSONBody(body string, params map[string]string, target interface{}, allowUnknownFields bool) error {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	if !allowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	return decoder.Decode(target)
+}
+
+// decodeFormBody decodes URL-encoded forms into the fields of a struct, using their 'form' tags (or 'json' tags)
+func decodeFormBody(body string, params map[string]string, target interface{}, allowUnknownFields bool) error {
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		return err
+	}
+
+	return bindForm(values, target, allowUnknownFields)
+}
+
+// decodeMultipartBody decodes the fields of multipart forms into the fields of a struct, using their 'form' tags (or
+// 'json' tags), within the parser's size limits. File parts are ignored
+func (p *bodyParser) decodeMultipartBody(body string, params map[string]string, target interface{}, allowUnknownFields bool) error {
+	form, err := readMultipartForm(body, params["boundary"], p.maxMultipartPartSize, p.maxMultipartTotalSize)
+	if err != nil {
+		return err
+	}
+
+	return bindForm(form.Fields, target, allowUnknownFields)
+}
+
+// decodeXMLBody decodes XML documents using the 'xml' tags of the target. XML is always decoded leniently, since
+// encoding/xml ignores unknown elements and attributes, so allowUnknownFields has no effect
+func decodeXMLBody(body string, params map[string]string, target interface{}, _ bool) error {
+	return xml.NewDecoder(strings.NewReader(body)).Decode(target)
+}
+
+func bindForm(values map[string][]string, target interface{}, allowUnknownFields bool) error {
+	unknown, err := bindValues(values, "form", target)
+	if err != nil {
+		return err
+	}
+	if len(unknown) > 0 && !allowUnknownFields {
+		return fmt.Errorf("unknown fields %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,379 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CloudEventsSpecVersion is the version of the CloudEvents specification supported
+const CloudEventsSpecVersion = "1.0"
+
+// ErrInvalidCloudEvent is returned when a payload is not a valid CloudEvent
+var ErrInvalidCloudEvent = errors.New("invalid CloudEvent")
+
+// CloudEvent represents an event in the CloudEvents (1.0) JSON format. Attributes that are not defined by the
+// specification are kept in Extensions
+type CloudEvent struct {
+	SpecVersion     string
+	ID              string
+	Source          string
+	Type            string
+	DataContentType string
+	DataSchema      string
+	Subject         string
+	Time            *time.Time
+	Data            json.RawMessage
+	DataBase64      string
+	Extensions      map[string]interface{}
+}
+
+var cloudEventAttributes = map[string]bool{
+	"specversion": true, "id": true, "source": true, "type": true, "datacontenttype": true, "dataschema": true,
+	"subject": true, "time": true, "data": true, "data_base64": true,
+}
+
+// MarshalJSON encodes the CloudEvent in the CloudEvents JSON format
+func (e CloudEvent) MarshalJSON() ([]byte, error) {
+	event := make(map[string]interface{}, len(e.Extensions)+10)
+	for k, v := range e.Extensions {
+		event[k] = v
+	}
+	event["specversion"] = e.SpecVersion
+	event["id"] = e.ID
+	event["source"] = e.Source
+	event["type"] = e.Type
+	for k, v := range map[string]string{"datacontenttype": e.DataContentType, "dataschema": e.DataSchema,
+		"subject": e.Subject, "data_base64": e.DataBase64} {
+		if v != "" {
+			event[k] = v
+		}
+	}
+	if e.Time != nil {
+		event["time"] = e.Time.Format(time.RFC3339Nano)
+	}
+	if len(e.Data) > 0 {
+		event["data"] = e.Data
+	}
+
+	return json.Marshal(event)
+}
+
+// UnmarshalJSON decodes a CloudEvent in the CloudEvents JSON format
+func (e *CloudEvent) UnmarshalJSON(data []byte) error {
+	var event map[string]json.RawMessage
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	*e = CloudEvent{Data: event["data"]}
+	for k, v := range map[string]*string{"specversion": &e.SpecVersion, "id": &e.ID, "source": &e.Source,
+		"type": &e.Type, "datacontenttype": &e.DataContentType, "dataschema": &e.DataSchema, "subject": &e.Subject,
+		"data_base64": &e.DataBase64} {
+		if raw, ok := event[k]; ok {
+			if err := json.Unmarshal(raw, v); err != nil {
+				return fmt.Errorf("attribute %s is not a string - %w", k, err)
+			}
+		}
+	}
+	if raw, ok := event["time"]; ok {
+		var t time.Time
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return fmt.Errorf("attribute time is not a RFC 3339 timestamp - %w", err)
+		}
+		e.Time = &t
+	}
+
+	for k, raw := range event {
+		if cloudEventAttributes[k] {
+			continue
+		}
+		if e.Extensions == nil {
+			e.Extensions = make(map[string]interface{})
+		}
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		e.Extensions[k] = v
+	}
+
+	return nil
+}
+
+func (e CloudEvent) validate() error {
+	var missing []string
+	for i, v := range []string{e.ID, e.Source, e.Type} {
+		if v == "" {
+			missing = append(missing, []string{"id", "source", "type"}[i])
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w - missing required attributes %s", ErrInvalidCloudEvent, strings.Join(missing, ", "))
+	}
+	if e.SpecVersion != CloudEventsSpecVersion {
+		return fmt.Errorf("%w - unsupported specversion %q", ErrInvalidCloudEvent, e.SpecVersion)
+	}
+
+	return nil
+}
+
+// payload returns the data of the CloudEvent as given to the Lambda handler: JSON data as is, binary data as a []byte,
+// and other data as a string
+func (e CloudEvent) payload() (interface{}, error) {
+	if e.DataBase64 != "" {
+		data, err := base64.StdEncoding.DecodeString(e.DataBase64)
+		if err != nil {
+			return nil, fmt.Errorf("%w - can't decode data_base64 - %v", ErrInvalidCloudEvent, err)
+		}
+		return data, nil
+	}
+	if len(e.Data) == 0 {
+		return nil, nil
+	}
+
+	return e.Data, nil
+}
+
+type cloudEventKey struct{}
+
+// GetCloudEvent returns the CloudEvent added to the context by ParseCloudEvent, if any. It gives access to the
+// attributes of the event whose data is being handled
+func GetCloudEvent(ctx context.Context) (CloudEvent, bool) {
+	event, ok := ctx.Value(cloudEventKey{}).(CloudEvent)
+	return event, ok
+}
+
+// ParseCloudEvent parses the CloudEvents received by the Lambda function, validates their required attributes
+// (specversion, id, source, and type), and calls the rest of the chain with their data, so it is decoded into the
+// Lambda handler's input type. The event itself is available through the context (see GetCloudEvent).
+//
+// API Gateway (REST and HTTP APIs) and Lambda Function URL requests can carry the event in structured mode (the body
+// is the event in JSON format) or binary mode (the attributes are 'ce-' headers and the body is the data). In binary
+// mode, JSON data is given to the chain as is, text data as a string, and data of other content types as a []byte.
+// SQS messages and SNS notifications carry the event in structured mode, and the chain is called once per record.
+// Failures are reported in a BatchError with the ID of each message. Invalid events fail with an error wrapping
+// ErrInvalidCloudEvent
+func ParseCloudEvent() gointercept.Interceptor {
+	return gointercept.Interceptor{
+		Around: func(next gointercept.LambdaHandler) gointercept.LambdaHandler {
+			return func(ctx context.Context, payload interface{}) (interface{}, error) {
+				source, err := DetectEventSource(payload)
+				if err != nil {
+					return payload, err
+				}
+				event, err := toTypedEvent(source, payload)
+				if err != nil {
+					return payload, err
+				}
+
+				switch e := event.(type) {
+				case events.APIGatewayProxyRequest:
+					return handleCloudEventRequest(ctx, next, e, e.Headers)
+				case events.APIGatewayV2HTTPRequest:
+					return handleCloudEventRequest(ctx, next, e, e.Headers)
+				case events.SQSEvent:
+					var batchError BatchError
+					var responses []interface{}
+					for _, record := range e.Records {
+						body, err := internal.GetBody(record)
+						if err != nil {
+							batchError.add(record.MessageId, err)
+							continue
+						}
+						response, err := handleStructuredCloudEvent(ctx, next, body)
+						if err != nil {
+							batchError.add(record.MessageId, err)
+							continue
+						}
+						responses = append(responses, response)
+					}
+					return responses, batchError.errorOrNil()
+				case events.SNSEvent:
+					var batchError BatchError
+					var responses []interface{}
+					for _, record := range e.Records {
+						response, err := handleStructuredCloudEvent(ctx, next, record.SNS.Message)
+						if err != nil {
+							batchError.add(record.SNS.MessageID, err)
+							continue
+						}
+						responses = append(responses, response)
+					}
+					return responses, batchError.errorOrNil()
+				}
+
+				return payload, fmt.Errorf("%w - CloudEvents can't be received from %s events", ErrInvalidCloudEvent, source)
+			}
+		},
+	}
+}
+
+func handleStructuredCloudEvent(ctx context.Context, next gointercept.LambdaHandler, body string) (interface{}, error) {
+	var cloudEvent CloudEvent
+	if err := json.Unmarshal([]byte(body), &cloudEvent); err != nil {
+		return nil, fmt.Errorf("%w - %v", ErrInvalidCloudEvent, err)
+	}
+	if err := cloudEvent.validate(); err != nil {
+		return nil, err
+	}
+
+	return handleCloudEvent(ctx, next, cloudEvent)
+}
+
+func handleCloudEvent(ctx context.Context, next gointercept.LambdaHandler, cloudEvent CloudEvent) (interface{}, error) {
+	data, err := cloudEvent.payload()
+	if err != nil {
+		return nil, err
+	}
+
+	return next(context.WithValue(ctx, cloudEventKey{}, cloudEvent), data)
+}
+
+// handleCloudEventRequest calls the rest of the chain with the CloudEvent carried by the given HTTP request. Invalid
+// events fail with a 400 HTTPError
+func handleCloudEventRequest(ctx context.Context, next gointercept.LambdaHandler, request interface{},
+	requestHeaders map[string]string) (interface{}, error) {
+	cloudEvent, err := cloudEventFromRequest(request, requestHeaders)
+	if err != nil {
+		return request, &HTTPError{StatusCode: http.StatusBadRequest, StatusText: err.Error(), Err: err}
+	}
+
+	return handleCloudEvent(ctx, next, cloudEvent)
+}
+
+func cloudEventFromRequest(request interface{}, requestHeaders map[string]string) (CloudEvent, error) {
+	var cloudEvent CloudEvent
+
+	headers := make(map[string]string, len(requestHeaders))
+	for k, v := range requestHeaders {
+		headers[strings.ToLower(k)] = v
+	}
+
+	body, err := internal.GetBody(request)
+	if err != nil {
+		return cloudEvent, fmt.Errorf("%w - can't read body - %v", ErrInvalidCloudEvent, err)
+	}
+
+	if _, ok := headers["ce-specversion"]; !ok {
+		if err := json.Unmarshal([]byte(body), &cloudEvent); err != nil {
+			return cloudEvent, fmt.Errorf("%w - %v", ErrInvalidCloudEvent, err)
+		}
+		return cloudEvent, cloudEvent.validate()
+	}
+
+	cloudEvent.DataContentType = headers["content-type"]
+	for k, v := range headers {
+		if !strings.HasPrefix(k, "ce-") {
+			continue
+		}
+		switch name := strings.TrimPrefix(k, "ce-"); name {
+		case "specversion":
+			cloudEvent.SpecVersion = v
+		case "id":
+			cloudEvent.ID = v
+		case "source":
+			cloudEvent.Source = v
+		case "type":
+			cloudEvent.Type = v
+		case "dataschema":
+			cloudEvent.DataSchema = v
+		case "subject":
+			cloudEvent.Subject = v
+		case "time":
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return cloudEvent, fmt.Errorf("%w - ce-time is not a RFC 3339 timestamp", ErrInvalidCloudEvent)
+			}
+			cloudEvent.Time = &t
+		default:
+			if cloudEvent.Extensions == nil {
+				cloudEvent.Extensions = make(map[string]interface{})
+			}
+			cloudEvent.Extensions[name] = v
+		}
+	}
+
+	switch {
+	case body == "":
+	case isJSONContentType(cloudEvent.DataContentType):
+		if !json.Valid([]byte(body)) {
+			return cloudEvent, fmt.Errorf("%w - data is not a valid JSON document", ErrInvalidCloudEvent)
+		}
+		cloudEvent.Data = json.RawMessage(body)
+	case isTextContentType(cloudEvent.DataContentType) && utf8.ValidString(body):
+		data, err := json.Marshal(body)
+		if err != nil {
+			return cloudEvent, fmt.Errorf("%w - can't encode data - %v", ErrInvalidCloudEvent, err)
+		}
+		cloudEvent.Data = data
+	default:
+		cloudEvent.DataBase64 = base64.StdEncoding.EncodeToString([]byte(body))
+	}
+
+	return cloudEvent, cloudEvent.validate()
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	return mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func isTextContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	return strings.HasPrefix(mediaType, "text/") || mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// CreateCloudEventResponse wraps the response of the Lambda function in a CloudEvent with the given source and type,
+// a new ID, and the current time. Responses that are already CloudEvents, and nil responses, are returned unchanged
+func CreateCloudEventResponse(source, eventType string) gointercept.Interceptor {
+	return gointercept.Interceptor{
+		After: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			if _, ok := payload.(CloudEvent); ok || payload == nil {
+				return payload, nil
+			}
+
+			data, err := internal.GetBytes(payload)
+			if err != nil {
+				return payload, err
+			}
+			id, err := newEventID()
+			if err != nil {
+				return payload, err
+			}
+			now := time.Now().UTC()
+
+			return CloudEvent{
+				SpecVersion:     CloudEventsSpecVersion,
+				ID:              id,
+				Source:          source,
+				Type:            eventType,
+				DataContentType: "application/json",
+				Time:            &now,
+				Data:            json.RawMessage(strings.TrimSpace(string(data))),
+			}, nil
+		},
+	}
+}
+
+// newEventID returns a random (version 4) UUID
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
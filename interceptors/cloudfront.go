@@ -0,0 +1,85 @@
+package interceptors
+
+import (
+	"strings"
+)
+
+// CloudFrontEvent represents the event received by Lambda@Edge functions. The aws-lambda-go events package does not
+// provide a type for it
+type CloudFrontEvent struct {
+	Records []CloudFrontRecord `json:"Records"`
+}
+
+// CloudFrontRecord contains a single CloudFront request or response
+type CloudFrontRecord struct {
+	CF CloudFrontRecordBody `json:"cf"`
+}
+
+// CloudFrontRecordBody contains the configuration of the distribution and the request or response of the event.
+// Viewer and origin request events carry only a request. Response events carry both
+type CloudFrontRecordBody struct {
+	Config   CloudFrontConfig    `json:"config"`
+	Request  *CloudFrontRequest  `json:"request,omitempty"`
+	Response *CloudFrontResponse `json:"response,omitempty"`
+}
+
+// CloudFrontConfig describes the distribution that triggered the event
+type CloudFrontConfig struct {
+	DistributionDomainName string `json:"distributionDomainName"`
+	DistributionID         string `json:"distributionId"`
+	EventType              string `json:"eventType"`
+	RequestID              string `json:"requestId"`
+}
+
+// CloudFrontRequest represents the request sent by the viewer or to the origin
+type CloudFrontRequest struct {
+	ClientIP    string                 `json:"clientIp"`
+	Method      string                 `json:"method"`
+	URI         string                 `json:"uri"`
+	QueryString string                 `json:"querystring"`
+	Headers     CloudFrontHeaders      `json:"headers"`
+	Body        *CloudFrontBody        `json:"body,omitempty"`
+	Origin      map[string]interface{} `json:"origin,omitempty"`
+}
+
+// CloudFrontBody represents the body of a CloudFront request
+type CloudFrontBody struct {
+	InputTruncated bool   `json:"inputTruncated"`
+	Action         string `json:"action"`
+	Encoding       string `json:"encoding"`
+	Data           string `json:"data"`
+}
+
+// CloudFrontResponse represents the response returned by the origin or sent to the viewer. Lambda@Edge functions may
+// also return it from request events to answer without contacting the origin
+type CloudFrontResponse struct {
+	Status            string            `json:"status"`
+	StatusDescription string            `json:"statusDescription,omitempty"`
+	Headers           CloudFrontHeaders `json:"headers"`
+	Body              string            `json:"body,omitempty"`
+	BodyEncoding      string            `json:"bodyEncoding,omitempty"`
+}
+
+// CloudFrontHeader represents a single value of a CloudFront header. Key holds the header's name as it is sent over
+// the wire
+type CloudFrontHeader struct {
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value"`
+}
+
+// CloudFrontHeaders maps the lowercase name of each header to its values, as required by CloudFront
+type CloudFrontHeaders map[string][]CloudFrontHeader
+
+// Get returns the first value of the given header, if any
+func (h CloudFrontHeaders) Get(key string) string {
+	if values := h[strings.ToLower(key)]; len(values) > 0 {
+		return values[0].Value
+	}
+
+	return ""
+}
+
+// Set replaces the values of the given header with the given value
+func (h CloudFrontHeaders) Set(key, value string) {
+	h[strings.ToLower(key)] = []CloudFrontHeader{{Key: key, Value: value}}
+}
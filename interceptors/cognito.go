@@ -0,0 +1,178 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"github.com/qri-io/jsonschema"
+	"strings"
+)
+
+const stringMapSchema = `{"type": ["object", "null"], "additionalProperties": {"type": "string"}}`
+
+// cognitoResponseSchemas maps the prefix of each Cognito User Pool trigger source to the schema of the response
+// section expected by Cognito
+var cognitoResponseSchemas = map[string]*jsonschema.Schema{
+	"PreSignUp_": jsonschema.Must(`{
+		"type": "object",
+		"properties": {
+			"autoConfirmUser": {"type": ["boolean", "null"]},
+			"autoVerifyEmail": {"type": ["boolean", "null"]},
+			"autoVerifyPhone": {"type": ["boolean", "null"]}
+		},
+		"additionalProperties": false
+	}`),
+	"PreAuthentication_":  jsonschema.Must(`{"type": "object", "additionalProperties": false}`),
+	"PostAuthentication_": jsonschema.Must(`{"type": "object", "additionalProperties": false}`),
+	"PostConfirmation_":   jsonschema.Must(`{"type": "object", "additionalProperties": false}`),
+	"TokenGeneration_": jsonschema.Must(`{
+		"type": "object",
+		"properties": {
+			"claimsOverrideDetails": {
+				"type": ["object", "null"],
+				"properties": {
+					"claimsToAddOrOverride": ` + stringMapSchema + `,
+					"claimsToSuppress": {"type": ["array", "null"], "items": {"type": "string"}},
+					"groupOverrideDetails": {
+						"type": ["object", "null"],
+						"properties": {
+							"groupsToOverride": {"type": ["array", "null"], "items": {"type": "string"}},
+							"iamRolesToOverride": {"type": ["array", "null"], "items": {"type": "string"}},
+							"preferredRole": {"type": ["string", "null"]}
+						},
+						"additionalProperties": false
+					}
+				},
+				"additionalProperties": false
+			}
+		},
+		"additionalProperties": false
+	}`),
+	"UserMigration_": jsonschema.Must(`{
+		"type": "object",
+		"properties": {
+			"userAttributes": ` + stringMapSchema + `,
+			"finalUserStatus": {"enum": ["", "CONFIRMED", "RESET_REQUIRED", null]},
+			"messageAction": {"enum": ["", "RESEND", "SUPPRESS", null]},
+			"desiredDeliveryMediums": {"type": ["array", "null"], "items": {"enum": ["SMS", "EMAIL"]}},
+			"forceAliasCreation": {"type": ["boolean", "null"]}
+		},
+		"additionalProperties": false
+	}`),
+	"DefineAuthChallenge_": jsonschema.Must(`{
+		"type": "object",
+		"properties": {
+			"challengeName": {"type": ["string", "null"]},
+			"issueTokens": {"type": ["boolean", "null"]},
+			"failAuthentication": {"type": ["boolean", "null"]}
+		},
+		"additionalProperties": false
+	}`),
+	"CreateAuthChallenge_": jsonschema.Must(`{
+		"type": "object",
+		"properties": {
+			"publicChallengeParameters": ` + stringMapSchema + `,
+			"privateChallengeParameters": ` + stringMapSchema + `,
+			"challengeMetadata": {"type": ["string", "null"]}
+		},
+		"additionalProperties": false
+	}`),
+	"VerifyAuthChallengeResponse_": jsonschema.Must(`{
+		"type": "object",
+		"properties": {
+			"answerCorrect": {"type": ["boolean", "null"]}
+		},
+		"additionalProperties": false
+	}`),
+	"CustomMessage_": jsonschema.Must(`{
+		"type": "object",
+		"properties": {
+			"smsMessage": {"type": ["string", "null"]},
+			"emailMessage": {"type": ["string", "null"]},
+			"emailSubject": {"type": ["string", "null"]}
+		},
+		"additionalProperties": false
+	}`),
+}
+
+// CreateCognitoTriggerResponse lets Cognito User Pool trigger handlers (pre sign-up, pre token generation, custom
+// message, etc.) return only the response section of the event (e.g. events.CognitoEventUserPoolsPreSignupResponse).
+// The returned section is merged into the response of the received event, which is echoed back in full as Cognito
+// requires. Handlers that return nothing echo the event unchanged.
+//
+// Before the event is returned, its response section is validated against the schema of the trigger (identified by
+// the event's triggerSource). Custom messages must also include the verification code placeholder sent by Cognito.
+// Invalid responses fail with an error instead of being sent to Cognito
+func CreateCognitoTriggerResponse() gointercept.Interceptor {
+	return gointercept.Interceptor{
+		Around: func(next gointercept.LambdaHandler) gointercept.LambdaHandler {
+			return func(ctx context.Context, payload interface{}) (interface{}, error) {
+				var event map[string]interface{}
+				if err := internal.Decode(payload, &event); err != nil {
+					return payload, fmt.Errorf("payload is not a Cognito trigger event - %w", err)
+				}
+
+				response, err := next(ctx, payload)
+				if err != nil {
+					return response, err
+				}
+
+				merged, _ := event["response"].(map[string]interface{})
+				if merged == nil {
+					merged = make(map[string]interface{})
+				}
+				if response != nil {
+					var section map[string]interface{}
+					if err := internal.Decode(response, &section); err != nil {
+						return response, fmt.Errorf("handler response is not a Cognito response section - %w", err)
+					}
+					for k, v := range section {
+						merged[k] = v
+					}
+				}
+
+				triggerSource, _ := event["triggerSource"].(string)
+				if err := validateCognitoResponse(ctx, triggerSource, event, merged); err != nil {
+					return response, err
+				}
+
+				event["response"] = merged
+				return event, nil
+			}
+		},
+	}
+}
+
+func validateCognitoResponse(ctx context.Context, triggerSource string, event map[string]interface{}, response map[string]interface{}) error {
+	for prefix, schema := range cognitoResponseSchemas {
+		if !strings.HasPrefix(triggerSource, prefix) {
+			continue
+		}
+
+		responseBytes, err := internal.GetBytes(response)
+		if err != nil {
+			return err
+		}
+		errs, err := schema.ValidateBytes(ctx, responseBytes)
+		if err != nil {
+			return err
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("invalid %s response - %w", triggerSource, newSchemaValidationError(errs, ""))
+		}
+	}
+
+	if strings.HasPrefix(triggerSource, "CustomMessage_") {
+		request, _ := event["request"].(map[string]interface{})
+		codeParameter, _ := request["codeParameter"].(string)
+		for _, key := range []string{"smsMessage", "emailMessage"} {
+			message, _ := response[key].(string)
+			if message != "" && codeParameter != "" && !strings.Contains(message, codeParameter) {
+				return fmt.Errorf("invalid %s response - %s does not contain the code parameter %s", triggerSource, key, codeParameter)
+			}
+		}
+	}
+
+	return nil
+}
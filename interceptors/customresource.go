@@ -0,0 +1,232 @@
+package interceptors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"io"
+	"net/http"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxCustomResourceReasonLength = 1024
+	maxCustomResourceDataSize     = 4096
+)
+
+// CustomResourceResult is returned by CloudFormation custom resource handlers wrapped with HandleCustomResource.
+// PhysicalResourceID defaults to the one already assigned to the resource, if any. Data values can be retrieved
+// with Fn::GetAtt in the stack's template. CloudFormation limits Data to 4096 bytes once serialized, so larger data
+// results in a FAILED response
+type CustomResourceResult struct {
+	PhysicalResourceID string                 `json:"physicalResourceId"`
+	Data               map[string]interface{} `json:"data"`
+	NoEcho             bool                   `json:"noEcho"`
+}
+
+type customResource struct {
+	client         *http.Client
+	deadlineMargin time.Duration
+}
+
+// CustomResourceOption represents a configuration option for the HandleCustomResource interceptor
+type CustomResourceOption func(*customResource)
+
+// CustomResourceHTTPClient sets the HTTP client used to send the response to CloudFormation
+func CustomResourceHTTPClient(client *http.Client) CustomResourceOption {
+	return func(c *customResource) {
+		c.client = client
+	}
+}
+
+// CustomResourceDeadlineMargin sets how long before the Lambda function's deadline a FAILED response is sent if
+// the handler is still running. It defaults to 5 seconds
+func CustomResourceDeadlineMargin(margin time.Duration) CustomResourceOption {
+	return func(c *customResource) {
+		c.deadlineMargin = margin
+	}
+}
+
+var errCustomResourceDeadline = errors.New("handler did not finish before the Lambda function's deadline")
+
+type customResourceOutcome struct {
+	response interface{}
+	err      error
+}
+
+// HandleCustomResource guarantees that a response is sent to the pre-signed URL (ResponseURL) of the CloudFormation
+// custom resource event (cfn.Event) received by the Lambda function. A SUCCESS response is sent when the handler
+// returns a CustomResourceResult. A FAILED response is sent when the handler returns an error, panics, or is still
+// running when the Lambda function's deadline approaches. Otherwise, the stack would wait for the response for an hour.
+// The context given to the handler is cancelled when that FAILED response is sent, so the handler can stop its work.
+//
+// The response sent is returned by the Lambda function. Errors are only returned when the response can't be sent,
+// since CloudFormation is notified of the handler's failure through the response
+func HandleCustomResource(options ...CustomResourceOption) gointercept.Interceptor {
+	customResource := customResource{client: http.DefaultClient, deadlineMargin: 5 * time.Second}
+	for _, opt := range options {
+		opt(&customResource)
+	}
+
+	return gointercept.Interceptor{
+		Around: func(next gointercept.LambdaHandler) gointercept.LambdaHandler {
+			return func(ctx context.Context, payload interface{}) (interface{}, error) {
+				var event cfn.Event
+				if err := internal.Decode(payload, &event); err != nil {
+					return payload, fmt.Errorf("payload is not a CloudFormation custom resource event - %w", err)
+				}
+
+				handlerCtx := ctx
+				var timeout <-chan struct{}
+				if deadline, ok := ctx.Deadline(); ok {
+					var cancel context.CancelFunc
+					handlerCtx, cancel = context.WithDeadline(ctx, deadline.Add(-customResource.deadlineMargin))
+					defer cancel()
+					timeout = handlerCtx.Done()
+				}
+
+				outcomes := make(chan customResourceOutcome, 1)
+				go func() {
+					defer func() {
+						if r := recover(); r != nil {
+							outcomes <- customResourceOutcome{err: fmt.Errorf("handler panicked: %v", r)}
+						}
+					}()
+					response, err := next(handlerCtx, event)
+					outcomes <- customResourceOutcome{response: response, err: err}
+				}()
+
+				var outcome customResourceOutcome
+				select {
+				case outcome = <-outcomes:
+					if outcome.err != nil && errors.Is(handlerCtx.Err(), context.DeadlineExceeded) {
+						outcome.err = errCustomResourceDeadline
+					}
+				case <-timeout:
+					outcome.err = errCustomResourceDeadline
+				}
+
+				response := newCustomResourceResponse(ctx, event, outcome)
+				if err := customResource.send(event.ResponseURL, response); err != nil {
+					return response, err
+				}
+
+				return response, nil
+			}
+		},
+	}
+}
+
+func newCustomResourceResponse(ctx context.Context, event cfn.Event, outcome customResourceOutcome) cfn.Response {
+	response := *cfn.NewResponse(&event)
+	response.PhysicalResourceID = event.PhysicalResourceID
+
+	var result CustomResourceResult
+	if outcome.err == nil && outcome.response != nil {
+		if err := internal.Decode(outcome.response, &result); err != nil {
+			outcome.err = fmt.Errorf("handler response is not a custom resource result - %w", err)
+		}
+	}
+	data := formatCustomResourceData(result.Data)
+	// The formatted values are strings, so the data can always be serialized
+	if b, _ := json.Marshal(data); outcome.err == nil && len(b) > maxCustomResourceDataSize {
+		outcome.err = fmt.Errorf("custom resource data is %d bytes, more than the %d bytes allowed", len(b),
+			maxCustomResourceDataSize)
+	}
+
+	if outcome.err != nil {
+		response.Status = cfn.StatusFailed
+		response.Reason = truncateReason(outcome.err.Error())
+	} else {
+		response.Status = cfn.StatusSuccess
+		if result.PhysicalResourceID != "" {
+			response.PhysicalResourceID = result.PhysicalResourceID
+		}
+		response.Data = data
+		response.NoEcho = result.NoEcho
+	}
+
+	if response.PhysicalResourceID == "" {
+		if lambdaContext, ok := lambdacontext.FromContext(ctx); ok && lambdacontext.LogStreamName != "" {
+			response.PhysicalResourceID = lambdacontext.LogStreamName + "/" + lambdaContext.AwsRequestID
+		} else {
+			response.PhysicalResourceID = event.LogicalResourceID + "-" + event.RequestID
+		}
+	}
+
+	return response
+}
+
+// truncateReason cuts the given reason to the maximum length allowed by CloudFormation without splitting a UTF-8
+// encoded character
+func truncateReason(reason string) string {
+	if len(reason) <= maxCustomResourceReasonLength {
+		return reason
+	}
+	n := maxCustomResourceReasonLength
+	for n > 0 && !utf8.RuneStart(reason[n]) {
+		n--
+	}
+
+	return reason[:n]
+}
+
+// formatCustomResourceData converts the values of the given map to strings, since Fn::GetAtt can only return
+// string attributes
+func formatCustomResourceData(data map[string]interface{}) map[string]interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+
+	formatted := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		switch value := v.(type) {
+		case string:
+			formatted[k] = value
+		case nil:
+			formatted[k] = ""
+		default:
+			b, err := json.Marshal(value)
+			if err != nil {
+				formatted[k] = fmt.Sprint(value)
+				continue
+			}
+			formatted[k] = string(b)
+		}
+	}
+
+	return formatted
+}
+
+func (c customResource) send(url string, response cfn.Response) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.ContentLength = int64(len(body))
+
+	httpResponse, err := c.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("can't send custom resource response - %w", err)
+	}
+	defer httpResponse.Body.Close()
+	_, _ = io.Copy(io.Discard, httpResponse.Body)
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("can't send custom resource response - unexpected status %d", httpResponse.StatusCode)
+	}
+
+	return nil
+}
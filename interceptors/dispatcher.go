@@ -0,0 +1,186 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"reflect"
+)
+
+// EventSource identifies the kind of event that triggered the Lambda function
+type EventSource string
+
+// Event sources recognized by DetectEventSource
+const (
+	APIGatewaySource     EventSource = "aws:apigateway"
+	APIGatewayV2Source   EventSource = "aws:apigatewayv2"
+	WebSocketSource      EventSource = "aws:apigateway:websocket"
+	SQSSource            EventSource = "aws:sqs"
+	SNSSource            EventSource = "aws:sns"
+	S3Source             EventSource = "aws:s3"
+	DynamoDBSource       EventSource = "aws:dynamodb"
+	KinesisSource        EventSource = "aws:kinesis"
+	KafkaSource          EventSource = "aws:kafka"
+	CloudFrontSource     EventSource = "aws:cloudfront"
+	ScheduledEventSource EventSource = "aws:events:scheduled"
+	EventBridgeSource    EventSource = "aws:events"
+)
+
+const selfManagedKafkaSource = "SelfManagedKafka"
+
+// ErrUnrecognizedEvent is returned when the source of an event can't be determined or no handler is registered for it
+var ErrUnrecognizedEvent = errors.New("unrecognized event")
+
+type recordProbe struct {
+	EventSource string                 `json:"eventSource"`
+	CF          map[string]interface{} `json:"cf"`
+}
+
+type eventProbe struct {
+	Records        json.RawMessage `json:"Records"`
+	EventSource    string          `json:"eventSource"`
+	HTTPMethod     string          `json:"httpMethod"`
+	Version        string          `json:"version"`
+	RequestContext struct {
+		ConnectionID string                 `json:"connectionId"`
+		HTTP         map[string]interface{} `json:"http"`
+	} `json:"requestContext"`
+	Source     string `json:"source"`
+	DetailType string `json:"detail-type"`
+}
+
+// DetectEventSource determines the kind of event the given payload represents. Typed events (e.g. events.SQSEvent)
+// are recognized by their type. Generic payloads are recognized by their shape
+func DetectEventSource(payload interface{}) (EventSource, error) {
+	switch event := payload.(type) {
+	case events.APIGatewayProxyRequest:
+		return APIGatewaySource, nil
+	case events.APIGatewayV2HTTPRequest:
+		return APIGatewayV2Source, nil
+	case events.APIGatewayWebsocketProxyRequest:
+		return WebSocketSource, nil
+	case events.SQSEvent:
+		return SQSSource, nil
+	case events.SNSEvent:
+		return SNSSource, nil
+	case events.S3Event:
+		return S3Source, nil
+	case events.DynamoDBEvent:
+		return DynamoDBSource, nil
+	case events.KinesisEvent:
+		return KinesisSource, nil
+	case events.KafkaEvent:
+		return KafkaSource, nil
+	case CloudFrontEvent:
+		return CloudFrontSource, nil
+	case events.CloudWatchEvent:
+		if event.DetailType == "Scheduled Event" {
+			return ScheduledEventSource, nil
+		}
+		return EventBridgeSource, nil
+	}
+
+	var probe eventProbe
+	if err := internal.Decode(payload, &probe); err != nil {
+		return "", fmt.Errorf("%w - %s", ErrUnrecognizedEvent, err.Error())
+	}
+
+	// Kafka events store their records in a map, as opposed to the Records list of other event sources
+	var records []recordProbe
+	_ = json.Unmarshal(probe.Records, &records)
+
+	switch {
+	case probe.EventSource == string(KafkaSource) || probe.EventSource == selfManagedKafkaSource:
+		return KafkaSource, nil
+	case len(records) > 0 && records[0].CF != nil:
+		return CloudFrontSource, nil
+	case len(records) > 0:
+		switch source := EventSource(records[0].EventSource); source {
+		case SQSSource, SNSSource, S3Source, DynamoDBSource, KinesisSource:
+			return source, nil
+		}
+	case probe.RequestContext.ConnectionID != "" && probe.HTTPMethod == "":
+		return WebSocketSource, nil
+	case probe.HTTPMethod != "":
+		return APIGatewaySource, nil
+	case probe.Version == "2.0" && probe.RequestContext.HTTP != nil:
+		return APIGatewayV2Source, nil
+	case probe.Source == "aws.events" && probe.DetailType == "Scheduled Event":
+		return ScheduledEventSource, nil
+	case probe.DetailType != "":
+		return EventBridgeSource, nil
+	}
+
+	return "", ErrUnrecognizedEvent
+}
+
+// Dispatcher routes each event to the handler registered for its source (see DetectEventSource). This allows a single
+// Lambda function to be triggered by several services. Each handler is usually a Lambda function already wrapped with
+// its own interceptors (gointercept.This(...).With(...)) and receives the event converted to its aws-lambda-go type
+// (e.g. events.SQSEvent), so typed interceptors can be used.
+//
+// Events that can't be recognized, or whose source has no handler, fail with an error wrapping ErrUnrecognizedEvent
+func Dispatcher(handlers map[EventSource]gointercept.LambdaHandler) gointercept.LambdaHandler {
+	return func(ctx context.Context, payload interface{}) (interface{}, error) {
+		source, err := DetectEventSource(payload)
+		if err != nil {
+			return payload, err
+		}
+
+		handler, ok := handlers[source]
+		if !ok {
+			return payload, fmt.Errorf("%w - no handler registered for %s events", ErrUnrecognizedEvent, source)
+		}
+
+		event, err := toTypedEvent(source, payload)
+		if err != nil {
+			return payload, err
+		}
+
+		return handler(ctx, event)
+	}
+}
+
+func toTypedEvent(source EventSource, payload interface{}) (interface{}, error) {
+	var event interface{}
+	switch source {
+	case APIGatewaySource:
+		event = &events.APIGatewayProxyRequest{}
+	case APIGatewayV2Source:
+		event = &events.APIGatewayV2HTTPRequest{}
+	case WebSocketSource:
+		event = &events.APIGatewayWebsocketProxyRequest{}
+	case SQSSource:
+		event = &events.SQSEvent{}
+	case SNSSource:
+		event = &events.SNSEvent{}
+	case S3Source:
+		event = &events.S3Event{}
+	case DynamoDBSource:
+		event = &events.DynamoDBEvent{}
+	case KinesisSource:
+		event = &events.KinesisEvent{}
+	case KafkaSource:
+		event = &events.KafkaEvent{}
+	case CloudFrontSource:
+		event = &CloudFrontEvent{}
+	case ScheduledEventSource, EventBridgeSource:
+		event = &events.CloudWatchEvent{}
+	default:
+		return payload, nil
+	}
+
+	eventType := reflect.TypeOf(event).Elem()
+	if reflect.TypeOf(payload) == eventType {
+		return payload, nil
+	}
+	if err := internal.Decode(payload, event); err != nil {
+		return payload, fmt.Errorf("payload is not a valid %s event - %w", source, err)
+	}
+
+	return reflect.ValueOf(event).Elem().Interface(), nil
+}
@@ -4,36 +4,50 @@ package interceptors
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/jpcedenog/gointercept"
 	"github.com/jpcedenog/gointercept/internal"
 	"net/http"
-	"strings"
+	"reflect"
 )
 
-// ParseBody parses the Lambda function's payload into the value pointed to by the input parameter
-func ParseBody(input interface{}, allowUnknownFields bool) gointercept.Interceptor {
-	var localPayload interface{}
+// ParseBody parses the Lambda function's payload into a new value of the type pointed to by the input parameter, which
+// must be a non-nil pointer. The input itself is not modified: a new value is used on every invocation, and for every
+// item of batched events, so fields parsed from previous payloads are never carried over.
+//
+// The decoder is chosen based on the request's Content-Type header: JSON (the default when there is no such header),
+// URL-encoded and multipart forms (using the 'form' or 'json' tags of the struct fields, see WithMultipartLimits for
+// the size of multipart forms), and XML (using 'xml' tags). XML is always decoded leniently: unknown elements and
+// attributes are ignored regardless of allowUnknownFields. Other media types can be supported with the
+// WithBodyDecoder option. Unsupported media types fail with a 415 HTTPError
+func ParseBody(input interface{}, allowUnknownFields bool, options ...ParseBodyOption) gointercept.Interceptor {
+	inputType := reflect.TypeOf(input)
+	parser := newBodyParser(options)
 	return gointercept.Interceptor{
 		Before: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			if inputType == nil || inputType.Kind() != reflect.Ptr || reflect.ValueOf(input).IsNil() {
+				err := fmt.Errorf("ParseBody needs a non-nil pointer, got %T", input)
+				return payload, &HTTPError{StatusCode: http.StatusInternalServerError, StatusText: err.Error(), Err: err}
+			}
+
+			decoder, params, err := parser.decoder(internal.GetHeader(payload, "Content-Type"))
+			if err != nil {
+				return payload, &HTTPError{StatusCode: http.StatusUnsupportedMediaType, StatusText: err.Error(), Err: err}
+			}
+
 			body, err := internal.GetBody(payload)
 			if err != nil {
 				return payload, err
 			}
-			localPayload = body
-			decoder := json.NewDecoder(strings.NewReader(body))
-			if !allowUnknownFields {
-				decoder.DisallowUnknownFields()
-			}
-			if err := decoder.Decode(input); err != nil {
-				return payload, fmt.Errorf("can't parse %#v - %w", localPayload, err)
+			value := reflect.New(inputType.Elem()).Interface()
+			if err := decoder(body, params, value, allowUnknownFields); err != nil {
+				return payload, fmt.Errorf("can't parse %#v - %w", body, err)
 			}
 
-			return input, nil
+			return value, nil
 		},
 		OnError: func(ctx context.Context, payload interface{}, err error) (interface{}, error) {
-			return payload, &HTTPError{http.StatusUnprocessableEntity, err.Error()}
+			return payload, toHTTPError(http.StatusUnprocessableEntity, err)
 		},
 	}
 }
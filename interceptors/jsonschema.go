@@ -3,41 +3,89 @@ package interceptors
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/jpcedenog/gointercept"
 	"github.com/jpcedenog/gointercept/internal"
 	"github.com/qri-io/jsonschema"
 	"net/http"
+	"strings"
 )
 
-// ValidateBodyJSONSchema validates the given payload (in JSON format) against the given JSON schema.
+// ValidateBodyJSONSchema validates the given payload (in JSON format) against the given JSON schema. The schema is
+// parsed once, when the interceptor is created. All validation errors, with the JSON pointer to each invalid value,
+// are reported together in a 422 HTTPError whose body is a JSON document, wrapping a ValidationError. See
+// NewBodyJSONSchemaValidator to detect invalid schemas when the interceptor is created.
 //
 // For more information check: https://github.com/qri-io/jsonschema
 func ValidateBodyJSONSchema(schema string) gointercept.Interceptor {
+	rs, err := compileJSONSchema(schema)
+	return bodyJSONSchemaValidator(rs, err)
+}
+
+// NewBodyJSONSchemaValidator returns an interceptor that validates the given payload (in JSON format) against the
+// given JSON schema, as ValidateBodyJSONSchema does, or an error if the schema is invalid
+func NewBodyJSONSchemaValidator(schema string) (gointercept.Interceptor, error) {
+	rs, err := compileJSONSchema(schema)
+	if err != nil {
+		return gointercept.Interceptor{}, err
+	}
+
+	return bodyJSONSchemaValidator(rs, nil), nil
+}
+
+func bodyJSONSchemaValidator(rs *jsonschema.Schema, schemaErr error) gointercept.Interceptor {
 	return gointercept.Interceptor{
 		Before: func(ctx context.Context, payload interface{}) (interface{}, error) {
-			body, err := internal.GetBody(payload)
-			if err != nil {
-				return payload, err
-			}
-
-			rs := &jsonschema.Schema{}
-			if err := json.Unmarshal([]byte(schema), rs); err != nil {
-				return payload, err
+			if schemaErr != nil {
+				return payload, schemaErr
 			}
 
-			errs, err := rs.ValidateBytes(ctx, []byte(body))
+			errs, err := validateBodyJSONSchema(ctx, rs, payload)
 			if err != nil {
 				return payload, err
 			}
 
 			if len(errs) > 0 {
-				return payload, errs[0]
+				return payload, newSchemaValidationError(errs, "").toHTTPError(http.StatusUnprocessableEntity)
 			}
 
 			return payload, nil
 		},
 		OnError: func(ctx context.Context, payload interface{}, err error) (interface{}, error) {
-			return payload, &HTTPError{http.StatusUnprocessableEntity, err.Error()}
+			return payload, toHTTPError(http.StatusUnprocessableEntity, err)
 		},
 	}
 }
+
+func compileJSONSchema(schema string) (*jsonschema.Schema, error) {
+	rs := &jsonschema.Schema{}
+	if err := json.Unmarshal([]byte(schema), rs); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema - %w", err)
+	}
+
+	return rs, nil
+}
+
+func validateBodyJSONSchema(ctx context.Context, rs *jsonschema.Schema, payload interface{}) ([]jsonschema.KeyError, error) {
+	body, err := internal.GetBody(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.ValidateBytes(ctx, []byte(body))
+}
+
+// newSchemaValidationError converts the given KeyErrors into a ValidationError. The given prefix is added to the JSON
+// pointer of each error
+func newSchemaValidationError(errs []jsonschema.KeyError, prefix string) *ValidationError {
+	violations := make([]FieldViolation, len(errs))
+	for i, keyError := range errs {
+		field := keyError.PropertyPath
+		if prefix != "" {
+			field = prefix + strings.TrimSuffix(field, "/")
+		}
+		violations[i] = FieldViolation{Field: field, Message: keyError.Message}
+	}
+
+	return &ValidationError{Violations: violations}
+}
@@ -0,0 +1,159 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"sort"
+	"time"
+)
+
+// KafkaValueFormat specifies how the values of Kafka records are decoded
+type KafkaValueFormat int
+
+const (
+	// KafkaJSONValue decodes record values as JSON documents (json.RawMessage)
+	KafkaJSONValue KafkaValueFormat = iota
+	// KafkaRawValue decodes record values as raw bytes ([]byte)
+	KafkaRawValue
+)
+
+// ErrKafkaRecordSkipped is reported for the records that were not processed because a previous record of the same
+// partition failed
+var ErrKafkaRecordSkipped = errors.New("skipped after a previous record of the partition failed")
+
+// KafkaMessage represents a decoded Kafka record. Value holds a json.RawMessage or a []byte depending on the
+// KafkaValueFormat used
+type KafkaMessage struct {
+	Topic     string            `json:"topic"`
+	Partition int64             `json:"partition"`
+	Offset    int64             `json:"offset"`
+	Timestamp time.Time         `json:"timestamp"`
+	Key       []byte            `json:"key"`
+	Value     interface{}       `json:"value"`
+	Headers   map[string]string `json:"headers"`
+}
+
+// ParseKafkaEvent decodes the base64-encoded records of a Kafka (MSK or self-managed) event into KafkaMessage
+// instances and calls the rest of the chain once per record or, if perPartition is true, once per topic-partition with
+// its records ([]KafkaMessage) in offset order.
+//
+// To preserve the ordering within each partition, the records of a partition that follow a failed record are not
+// processed. In per-partition mode, the records that precede a record that can't be decoded are still given to the
+// chain. Other partitions are processed regardless. The failed records, and those skipped because of them
+// (ErrKafkaRecordSkipped), are reported in a BatchError with IDs in the 'topic-partition@offset' form
+func ParseKafkaEvent(perPartition bool, format KafkaValueFormat) gointercept.Interceptor {
+	return gointercept.Interceptor{
+		Around: func(next gointercept.LambdaHandler) gointercept.LambdaHandler {
+			return func(ctx context.Context, payload interface{}) (interface{}, error) {
+				event, ok := payload.(events.KafkaEvent)
+				if !ok {
+					if err := internal.Decode(payload, &event); err != nil {
+						return payload, fmt.Errorf("payload is not a Kafka event - %w", err)
+					}
+				}
+
+				partitions := make([]string, 0, len(event.Records))
+				for partition := range event.Records {
+					partitions = append(partitions, partition)
+				}
+				sort.Strings(partitions)
+
+				var batchError BatchError
+				var responses []interface{}
+				for _, partition := range partitions {
+					records := append([]events.KafkaRecord(nil), event.Records[partition]...)
+					sort.SliceStable(records, func(i, j int) bool { return records[i].Offset < records[j].Offset })
+
+					messages := make([]KafkaMessage, 0, len(records))
+					failed := -1
+					var failure error
+					for i, record := range records {
+						message, err := decodeKafkaRecord(record, format)
+						if err == nil && !perPartition {
+							var response interface{}
+							response, err = next(ctx, message)
+							responses = append(responses, response)
+						}
+						if err != nil {
+							failed, failure = i, err
+							break
+						}
+						messages = append(messages, message)
+					}
+
+					if perPartition && len(messages) > 0 {
+						response, err := next(ctx, messages)
+						if err != nil {
+							for _, record := range records[:len(messages)] {
+								batchError.add(kafkaRecordID(record), err)
+							}
+						}
+						responses = append(responses, response)
+					}
+					if failed >= 0 {
+						batchError.add(kafkaRecordID(records[failed]), failure)
+						for _, skipped := range records[failed+1:] {
+							batchError.add(kafkaRecordID(skipped), ErrKafkaRecordSkipped)
+						}
+					}
+				}
+
+				return responses, batchError.errorOrNil()
+			}
+		},
+	}
+}
+
+func decodeKafkaRecord(record events.KafkaRecord, format KafkaValueFormat) (KafkaMessage, error) {
+	message := KafkaMessage{
+		Topic:     record.Topic,
+		Partition: record.Partition,
+		Offset:    record.Offset,
+		Timestamp: record.Timestamp.Time,
+	}
+
+	if record.Key != "" {
+		key, err := base64.StdEncoding.DecodeString(record.Key)
+		if err != nil {
+			return message, fmt.Errorf("can't decode key - %w", err)
+		}
+		message.Key = key
+	}
+
+	if len(record.Headers) > 0 {
+		message.Headers = make(map[string]string)
+		for _, header := range record.Headers {
+			for k, v := range header {
+				message.Headers[k] = string(v)
+			}
+		}
+	}
+
+	value, err := base64.StdEncoding.DecodeString(record.Value)
+	if err != nil {
+		return message, fmt.Errorf("can't decode value - %w", err)
+	}
+	if len(value) == 0 {
+		return message, nil
+	}
+	if format == KafkaJSONValue {
+		if !json.Valid(value) {
+			return message, fmt.Errorf("value is not a valid JSON document")
+		}
+		message.Value = json.RawMessage(value)
+	} else {
+		message.Value = value
+	}
+
+	return message, nil
+}
+
+func kafkaRecordID(record events.KafkaRecord) string {
+	return fmt.Sprintf("%s-%d@%d", record.Topic, record.Partition, record.Offset)
+}
@@ -0,0 +1,135 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// MultipartFile represents a file part of a multipart form
+type MultipartFile struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	Content     []byte `json:"content"`
+}
+
+// MultipartForm represents a parsed multipart form. Fields and files are keyed by their form name
+type MultipartForm struct {
+	Fields map[string][]string        `json:"fields"`
+	Files  map[string][]MultipartFile `json:"files"`
+}
+
+// Value returns the first value of the given field, or an empty string if there is none
+func (f MultipartForm) Value(name string) string {
+	if values := f.Fields[name]; len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// File returns the first file of the given field, if any
+func (f MultipartForm) File(name string) (MultipartFile, bool) {
+	if files := f.Files[name]; len(files) > 0 {
+		return files[0], true
+	}
+
+	return MultipartFile{}, false
+}
+
+type multipartFormKey struct{}
+
+// GetMultipartForm returns the multipart form added to the context by ParseMultipartForm, if any
+func GetMultipartForm(ctx context.Context) (MultipartForm, bool) {
+	form, ok := ctx.Value(multipartFormKey{}).(MultipartForm)
+	return form, ok
+}
+
+// ParseMultipartForm parses 'multipart/form-data' request bodies (e.g. browser uploads), base64-encoded or not, into
+// a MultipartForm with their fields and files. The form is given to the rest of the chain, so the Lambda handler can
+// receive it as its input, and it is available through the context as well (see GetMultipartForm).
+//
+// Parts larger than maxPartSize, or bodies larger than maxTotalSize, are rejected with a 413 HTTPError. Zero means
+// no limit. Malformed bodies are rejected with a 400 HTTPError, and other media types with a 415 HTTPError
+func ParseMultipartForm(maxPartSize, maxTotalSize int64) gointercept.Interceptor {
+	return gointercept.Interceptor{
+		Around: func(next gointercept.LambdaHandler) gointercept.LambdaHandler {
+			return func(ctx context.Context, payload interface{}) (interface{}, error) {
+				contentType := internal.GetHeader(payload, "Content-Type")
+				mediaType, params, err := mime.ParseMediaType(contentType)
+				if err != nil || mediaType != "multipart/form-data" {
+					return payload, &HTTPError{StatusCode: http.StatusUnsupportedMediaType,
+						StatusText: fmt.Sprintf("unsupported Content-Type %q - expected multipart/form-data", contentType)}
+				}
+
+				body, err := internal.GetBody(payload)
+				if err != nil {
+					return payload, &HTTPError{StatusCode: http.StatusBadRequest, StatusText: err.Error(), Err: err}
+				}
+				form, err := readMultipartForm(body, params["boundary"], maxPartSize, maxTotalSize)
+				if err != nil {
+					return payload, err
+				}
+
+				return next(context.WithValue(ctx, multipartFormKey{}, form), form)
+			}
+		},
+	}
+}
+
+// readMultipartForm parses the given multipart body. Parts larger than maxPartSize, or bodies larger than maxTotalSize,
+// are rejected with a 413 HTTPError (zero means no limit), and malformed bodies with a 400 HTTPError
+func readMultipartForm(body, boundary string, maxPartSize, maxTotalSize int64) (MultipartForm, error) {
+	form := MultipartForm{Fields: make(map[string][]string), Files: make(map[string][]MultipartFile)}
+	if maxTotalSize > 0 && int64(len(body)) > maxTotalSize {
+		return form, &HTTPError{StatusCode: http.StatusRequestEntityTooLarge,
+			StatusText: fmt.Sprintf("request body exceeds %d bytes", maxTotalSize)}
+	}
+	if boundary == "" {
+		return form, &HTTPError{StatusCode: http.StatusBadRequest, StatusText: "multipart boundary not found"}
+	}
+
+	reader := multipart.NewReader(strings.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return form, nil
+		}
+		if err != nil {
+			return form, &HTTPError{StatusCode: http.StatusBadRequest, StatusText: fmt.Sprintf("malformed multipart body - %s", err), Err: err}
+		}
+
+		var partReader io.Reader = part
+		if maxPartSize > 0 {
+			partReader = io.LimitReader(part, maxPartSize+1)
+		}
+		content, err := io.ReadAll(partReader)
+		if err != nil {
+			return form, &HTTPError{StatusCode: http.StatusBadRequest, StatusText: fmt.Sprintf("malformed multipart body - %s", err), Err: err}
+		}
+		if maxPartSize > 0 && int64(len(content)) > maxPartSize {
+			return form, &HTTPError{StatusCode: http.StatusRequestEntityTooLarge,
+				StatusText: fmt.Sprintf("part %q exceeds %d bytes", part.FormName(), maxPartSize)}
+		}
+
+		name := part.FormName()
+		if part.FileName() == "" {
+			form.Fields[name] = append(form.Fields[name], string(content))
+			continue
+		}
+		form.Files[name] = append(form.Files[name], MultipartFile{
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Size:        int64(len(content)),
+			Content:     content,
+		})
+	}
+}
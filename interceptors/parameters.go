@@ -0,0 +1,125 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+type parametersKey struct{}
+
+// GetParameters returns the value, a pointer to the type given to BindParameters, added to the context by
+// BindParameters, if any
+func GetParameters(ctx context.Context) interface{} {
+	return ctx.Value(parametersKey{})
+}
+
+// BindParameters binds the path parameters, query string parameters, and headers of API Gateway requests to a new
+// value of the type pointed to by the input parameter, which must be a non-nil pointer to a struct. The new value is
+// available through the context (see GetParameters). The payload is given to the rest of the chain unchanged, so the
+// body can be parsed as well.
+//
+// Struct fields are bound with the 'path', 'query', and 'header' tags (e.g. `query:"limit"`). Header names are
+// case-insensitive. Values are converted to the type of each field, and slices receive all the values of multi-value
+// parameters. The 'default' tag provides the value of missing parameters (comma-separated for slices). Parameters
+// whose values can't be converted are rejected with a 400 HTTPError
+func BindParameters(input interface{}) gointercept.Interceptor {
+	inputType := reflect.TypeOf(input)
+	return gointercept.Interceptor{
+		Around: func(next gointercept.LambdaHandler) gointercept.LambdaHandler {
+			return func(ctx context.Context, payload interface{}) (interface{}, error) {
+				if inputType == nil || inputType.Kind() != reflect.Ptr || reflect.ValueOf(input).IsNil() ||
+					inputType.Elem().Kind() != reflect.Struct {
+					err := fmt.Errorf("BindParameters needs a non-nil pointer to a struct, got %T", input)
+					return payload, &HTTPError{StatusCode: http.StatusInternalServerError, StatusText: err.Error(), Err: err}
+				}
+
+				request, err := getAPIGatewayProxyRequest(payload)
+				if err != nil {
+					return payload, err
+				}
+				for k, v := range PathParameters(ctx) {
+					if request.PathParameters == nil {
+						request.PathParameters = make(map[string]string)
+					}
+					request.PathParameters[k] = v
+				}
+
+				value := reflect.New(inputType.Elem())
+				if err := bindParameters(request, value.Elem()); err != nil {
+					return payload, &HTTPError{StatusCode: http.StatusBadRequest, StatusText: err.Error(), Err: err}
+				}
+
+				return next(context.WithValue(ctx, parametersKey{}, value.Interface()), payload)
+			}
+		},
+	}
+}
+
+func bindParameters(request events.APIGatewayProxyRequest, value reflect.Value) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		for _, kind := range []string{"path", "query", "header"} {
+			name, ok := field.Tag.Lookup(kind)
+			if !ok {
+				continue
+			}
+
+			values := parameterValues(request, kind, name)
+			if len(values) == 0 {
+				defaultValue, ok := field.Tag.Lookup("default")
+				if !ok {
+					continue
+				}
+				values = []string{defaultValue}
+				if field.Type.Kind() == reflect.Slice {
+					values = strings.Split(defaultValue, ",")
+				}
+			}
+
+			if err := setValues(value.Field(i), values); err != nil {
+				return fmt.Errorf("invalid %s parameter %s - %w", kind, name, err)
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+func parameterValues(request events.APIGatewayProxyRequest, kind, name string) []string {
+	switch kind {
+	case "path":
+		if v, ok := request.PathParameters[name]; ok {
+			return []string{v}
+		}
+	case "query":
+		if v, ok := request.MultiValueQueryStringParameters[name]; ok {
+			return v
+		}
+		if v, ok := request.QueryStringParameters[name]; ok {
+			return []string{v}
+		}
+	case "header":
+		for k, v := range request.MultiValueHeaders {
+			if strings.EqualFold(k, name) {
+				return v
+			}
+		}
+		for k, v := range request.Headers {
+			if strings.EqualFold(k, name) {
+				return []string{v}
+			}
+		}
+	}
+
+	return nil
+}
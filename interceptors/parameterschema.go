@@ -0,0 +1,207 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/qri-io/jsonschema"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ParameterSchemas contains the JSON schemas the parameters of API Gateway requests are validated against. Each
+// schema describes an object with a property per parameter. Empty schemas are not enforced
+type ParameterSchemas struct {
+	Path    string
+	Query   string
+	Headers string
+}
+
+// parameterSchema is a compiled parameter schema along with the types of its properties, used to coerce the string
+// values of the parameters
+type parameterSchema struct {
+	location string
+	schema   *jsonschema.Schema
+	types    map[string]propertyTypes
+}
+
+type propertyTypes struct {
+	types     []string
+	itemTypes []string
+}
+
+// NewParametersJSONSchemaValidator returns an interceptor that validates the path parameters, query string parameters,
+// and headers of API Gateway requests against their own JSON schemas, or an error if a schema is invalid.
+//
+// Since parameters are strings, their values are converted to the type declared by the schema for each property
+// ('integer', 'number', 'boolean', or 'array' for multi-value parameters). Only the headers declared by the schema
+// are validated, matched case-insensitively. All validation errors, with the JSON pointer to each invalid value (e.g.
+// '/query/limit'), are reported together in a 400 HTTPError whose body is a JSON document, wrapping a ValidationError
+func NewParametersJSONSchemaValidator(schemas ParameterSchemas) (gointercept.Interceptor, error) {
+	var compiled []parameterSchema
+	for _, s := range []struct{ location, schema string }{
+		{"path", schemas.Path}, {"query", schemas.Query}, {"headers", schemas.Headers},
+	} {
+		if s.schema == "" {
+			continue
+		}
+		ps, err := compileParameterSchema(s.location, s.schema)
+		if err != nil {
+			return gointercept.Interceptor{}, err
+		}
+		compiled = append(compiled, ps)
+	}
+
+	return gointercept.Interceptor{
+		Before: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			request, err := getAPIGatewayProxyRequest(payload)
+			if err != nil {
+				return payload, err
+			}
+
+			var validationError ValidationError
+			for _, ps := range compiled {
+				parameters, err := json.Marshal(ps.parameters(ctx, request))
+				if err != nil {
+					return payload, err
+				}
+
+				errs, err := ps.schema.ValidateBytes(ctx, parameters)
+				if err != nil {
+					return payload, err
+				}
+				violations := newSchemaValidationError(errs, "/"+ps.location).Violations
+				validationError.Violations = append(validationError.Violations, violations...)
+			}
+
+			if len(validationError.Violations) > 0 {
+				return payload, validationError.toHTTPError(http.StatusBadRequest)
+			}
+
+			return payload, nil
+		},
+	}, nil
+}
+
+func compileParameterSchema(location, schema string) (parameterSchema, error) {
+	rs, err := compileJSONSchema(schema)
+	if err != nil {
+		return parameterSchema{}, fmt.Errorf("invalid %s schema - %w", location, err)
+	}
+
+	var declared struct {
+		Properties map[string]struct {
+			Type  interface{} `json:"type"`
+			Items struct {
+				Type interface{} `json:"type"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(schema), &declared); err != nil {
+		return parameterSchema{}, fmt.Errorf("invalid %s schema - %w", location, err)
+	}
+
+	types := make(map[string]propertyTypes, len(declared.Properties))
+	for name, property := range declared.Properties {
+		types[name] = propertyTypes{types: schemaTypeNames(property.Type), itemTypes: schemaTypeNames(property.Items.Type)}
+	}
+
+	return parameterSchema{location: location, schema: rs, types: types}, nil
+}
+
+// parameters builds the object validated by the schema: path parameters (merged with those found by the Router),
+// query string parameters, or the headers declared by the schema
+func (ps parameterSchema) parameters(ctx context.Context, request events.APIGatewayProxyRequest) map[string]interface{} {
+	parameters := make(map[string]interface{})
+
+	switch ps.location {
+	case "path":
+		for k, v := range request.PathParameters {
+			parameters[k] = ps.coerce(k, []string{v})
+		}
+		for k, v := range PathParameters(ctx) {
+			parameters[k] = ps.coerce(k, []string{v})
+		}
+	case "query":
+		for k, v := range request.QueryStringParameters {
+			parameters[k] = ps.coerce(k, []string{v})
+		}
+		for k, v := range request.MultiValueQueryStringParameters {
+			parameters[k] = ps.coerce(k, v)
+		}
+	case "headers":
+		for name := range ps.types {
+			for k, v := range request.Headers {
+				if strings.EqualFold(k, name) {
+					parameters[name] = ps.coerce(name, []string{v})
+				}
+			}
+			for k, v := range request.MultiValueHeaders {
+				if strings.EqualFold(k, name) {
+					parameters[name] = ps.coerce(name, v)
+				}
+			}
+		}
+	}
+
+	return parameters
+}
+
+// coerce converts the given values of a parameter to the type declared by the schema. Values that can't be converted
+// are kept as strings, so the schema reports them
+func (ps parameterSchema) coerce(name string, values []string) interface{} {
+	types := ps.types[name]
+	if contains(types.types, "array") || (len(types.types) == 0 && len(values) > 1) {
+		items := make([]interface{}, len(values))
+		for i, v := range values {
+			items[i] = coerceParameter(v, types.itemTypes)
+		}
+		return items
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	return coerceParameter(values[0], types.types)
+}
+
+func coerceParameter(value string, types []string) interface{} {
+	for _, t := range types {
+		switch t {
+		case "integer":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return n
+			}
+		case "number":
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				return n
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(value); err == nil {
+				return b
+			}
+		}
+	}
+
+	return value
+}
+
+func schemaTypeNames(t interface{}) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		names := make([]string, 0, len(t))
+		for _, name := range t {
+			if s, ok := name.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+
+	return nil
+}
@@ -16,9 +16,30 @@ var exceptionsMap = getExceptionsMap([]string{"ALPN", "C-PEP", "C-PEP-Info", "Ca
 // NormalizeHTTPRequestHeaders captures the headers (single and multi-value) sent in the API Gateway (HTTP) request and
 // normalizes them to either an all-lowercase form or to their canonical form (content-type as opposed to Content-Type)
 // based on the value of the given 'canonical' parameter.
+//
+// Lambda Function URL and CloudFront (Lambda@Edge) requests are supported as well. Since CloudFront requires the keys
+// of its header map to be lowercase, only the name stored in each header's 'key' field is normalized in that case.
 func NormalizeHTTPRequestHeaders(canonical bool) gointercept.Interceptor {
 	return gointercept.Interceptor{
 		Before: func(context context.Context, payload interface{}) (interface{}, error) {
+			switch request := payload.(type) {
+			case CloudFrontEvent:
+				for _, record := range request.Records {
+					if record.CF.Request != nil {
+						normalizeCloudFrontHeaders(record.CF.Request.Headers, canonical)
+					}
+				}
+				return request, nil
+			case CloudFrontRequest:
+				normalizeCloudFrontHeaders(request.Headers, canonical)
+				return request, nil
+			case events.LambdaFunctionURLRequest:
+				for key, value := range request.Headers {
+					request.Headers[normalizeKey(key, canonical)] = value
+				}
+				return request, nil
+			}
+
 			if apiGatewayRequest, ok := payload.(events.APIGatewayProxyRequest); ok {
 				if apiGatewayRequest.Headers != nil {
 					for key, value := range apiGatewayRequest.Headers {
@@ -40,6 +61,20 @@ func NormalizeHTTPRequestHeaders(canonical bool) gointercept.Interceptor {
 	}
 }
 
+func normalizeCloudFrontHeaders(headers CloudFrontHeaders, canonical bool) {
+	for key, values := range headers {
+		normalized := make([]CloudFrontHeader, len(values))
+		for i, value := range values {
+			name := value.Key
+			if name == "" {
+				name = key
+			}
+			normalized[i] = CloudFrontHeader{Key: normalizeKey(name, canonical), Value: value.Value}
+		}
+		headers[key] = normalized
+	}
+}
+
 func getExceptionsMap(exceptions []string) map[string]string {
 	exceptionsMap := make(map[string]string)
 	for _, e := range exceptions {
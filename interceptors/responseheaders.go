@@ -97,10 +97,33 @@ func ReferrerPolicy(policy string) Option {
 }
 
 // AddHeaders attaches the given key-value mappings as HTTP headers to the given payload. It assumes that the payload
-// is already an APIGatewayProxyResponse. Otherwise, no headers are added
+// is already an APIGatewayProxyResponse, a LambdaFunctionURLResponse, a CloudFrontResponse, or a CloudFrontRequest.
+// Otherwise, no headers are added. 'Set-Cookie' headers are added to the cookies of Lambda Function URL responses.
+//
+// If the payload is a CloudFrontEvent (e.g. returned unchanged by a Lambda@Edge function), the headers are added to
+// its response (or its request, for request events), which is returned in place of the event as CloudFront expects
 func AddHeaders(headers map[string]string) gointercept.Interceptor {
 	return gointercept.Interceptor{
 		After: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			switch response := payload.(type) {
+			case CloudFrontEvent:
+				if len(response.Records) > 0 {
+					if cloudFrontResponse := response.Records[0].CF.Response; cloudFrontResponse != nil {
+						return addCloudFrontResponseHeaders(*cloudFrontResponse, headers), nil
+					}
+					if cloudFrontRequest := response.Records[0].CF.Request; cloudFrontRequest != nil {
+						return addCloudFrontRequestHeaders(*cloudFrontRequest, headers), nil
+					}
+				}
+				return payload, nil
+			case CloudFrontResponse:
+				return addCloudFrontResponseHeaders(response, headers), nil
+			case CloudFrontRequest:
+				return addCloudFrontRequestHeaders(response, headers), nil
+			case events.LambdaFunctionURLResponse:
+				return addFunctionURLResponseHeaders(response, headers), nil
+			}
+
 			if apiGatewayResponse, ok := payload.(events.APIGatewayProxyResponse); ok {
 				if apiGatewayResponse.Headers == nil {
 					apiGatewayResponse.Headers = make(map[string]string)
@@ -120,6 +143,43 @@ func AddHeaders(headers map[string]string) gointercept.Interceptor {
 	}
 }
 
+func addFunctionURLResponseHeaders(response events.LambdaFunctionURLResponse, headers map[string]string) events.LambdaFunctionURLResponse {
+	if response.Headers == nil {
+		response.Headers = make(map[string]string)
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, "Set-Cookie") {
+			response.Cookies = append(response.Cookies, v)
+			continue
+		}
+		response.Headers[k] = v
+	}
+
+	return response
+}
+
+func addCloudFrontResponseHeaders(response CloudFrontResponse, headers map[string]string) CloudFrontResponse {
+	if response.Headers == nil {
+		response.Headers = make(CloudFrontHeaders)
+	}
+	for k, v := range headers {
+		response.Headers.Set(k, v)
+	}
+
+	return response
+}
+
+func addCloudFrontRequestHeaders(request CloudFrontRequest, headers map[string]string) CloudFrontRequest {
+	if request.Headers == nil {
+		request.Headers = make(CloudFrontHeaders)
+	}
+	for k, v := range headers {
+		request.Headers.Set(k, v)
+	}
+
+	return request
+}
+
 // AddSecurityHeaders attaches default HTTP security headers to the output returned by the Lambda function.
 // This is similar to the functionality offered by HelmetJS. For more information on the headers added by this
 // interceptor check (https://helmetjs.github.io/)
@@ -127,6 +187,9 @@ func AddHeaders(headers map[string]string) gointercept.Interceptor {
 // Optionally, this interceptor's behavior can be customized by passing functions to activate, deactivate, or
 // modify the functionality of the default headers. These functions include: DNSPrefetchControl, FrameGuard,
 // HidePoweredBy, HTTPStrictTransportSecurity, IENoOpen, NoSniff, and ReferrerPolicy.
+//
+// Like AddHeaders, this interceptor supports Lambda Function URL responses and CloudFront (Lambda@Edge) viewer and
+// origin responses.
 func AddSecurityHeaders(options ...Option) gointercept.Interceptor {
 	securityHeaders := getDefaults()
 
@@ -2,6 +2,8 @@ package interceptors
 
 import (
 	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
 	"github.com/jpcedenog/gointercept"
 	"github.com/jpcedenog/gointercept/internal"
 )
@@ -18,16 +20,35 @@ type DefaultStatusCodes struct {
 //
 // Optionally, an interceptor can throw this type of error with the corresponding code and status text.
 // Then, the CreateAPIGatewayProxyResponse interceptor will create the appropriate API Gateway response
-// used the information provided by it
+// used the information provided by it. Headers, if any, are added to that response. Err optionally holds the
+// error that caused it, so it can still be matched with errors.Is and errors.As
 type HTTPError struct {
 	StatusCode int
 	StatusText string
+	Headers    map[string]string
+	Err        error
 }
 
 func (e *HTTPError) Error() string {
 	return e.StatusText
 }
 
+// Unwrap returns the error that caused the HTTPError, if any
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// toHTTPError converts the given error into an HTTPError with the given status code, unless it's an HTTPError already.
+// The original error is kept as the cause of the HTTPError, so it can still be matched with errors.Is and errors.As
+func toHTTPError(statusCode int, err error) error {
+	var httpError *HTTPError
+	if errors.As(err, &httpError) {
+		return httpError
+	}
+
+	return &HTTPError{StatusCode: statusCode, StatusText: err.Error(), Err: err}
+}
+
 // CreateAPIGatewayProxyResponse wraps the output of the Lambda function with an APIGatewayProxyResponse instance
 func CreateAPIGatewayProxyResponse(defaultStatusCode *DefaultStatusCodes) gointercept.Interceptor {
 	return gointercept.Interceptor{
@@ -50,6 +71,15 @@ func CreateAPIGatewayProxyResponse(defaultStatusCode *DefaultStatusCodes) gointe
 			if httpError, ok := err.(*HTTPError); ok {
 				response.Body = httpError.StatusText
 				response.StatusCode = httpError.StatusCode
+				response.IsBase64Encoded = false
+				if len(httpError.Headers) > 0 {
+					if response.Headers == nil {
+						response.Headers = make(map[string]string)
+					}
+					for k, v := range httpError.Headers {
+						response.Headers[k] = v
+					}
+				}
 				return response, nil
 			}
 
@@ -60,3 +90,43 @@ func CreateAPIGatewayProxyResponse(defaultStatusCode *DefaultStatusCodes) gointe
 		},
 	}
 }
+
+// CreateFunctionURLResponse wraps the output of the Lambda function with a LambdaFunctionURLResponse instance.
+// Errors are turned into responses as well: HTTPErrors with their own status code and headers, and other errors
+// with the default error status code
+func CreateFunctionURLResponse(defaultStatusCode *DefaultStatusCodes) gointercept.Interceptor {
+	return gointercept.Interceptor{
+		After: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			response, err := internal.ConvertToFunctionURLResponse(payload)
+			if err != nil {
+				return payload, err
+			}
+			if response.StatusCode == 0 && defaultStatusCode != nil {
+				response.StatusCode = defaultStatusCode.Success
+			}
+
+			return response, nil
+		},
+		OnError: func(ctx context.Context, payload interface{}, err error) (interface{}, error) {
+			response := events.LambdaFunctionURLResponse{Body: err.Error()}
+			var httpError *HTTPError
+			if errors.As(err, &httpError) {
+				response.StatusCode = httpError.StatusCode
+				response.Body = httpError.StatusText
+				if len(httpError.Headers) > 0 {
+					response.Headers = make(map[string]string, len(httpError.Headers))
+					for k, v := range httpError.Headers {
+						response.Headers[k] = v
+					}
+				}
+				return response, nil
+			}
+			if defaultStatusCode == nil {
+				return payload, err
+			}
+
+			response.StatusCode = defaultStatusCode.Error
+			return response, nil
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"log"
+	"net/http"
+)
+
+// ResponseValidationMode specifies what happens when a response does not match its JSON schema
+type ResponseValidationMode int
+
+const (
+	// LogResponseViolations logs the violations and returns the response unchanged
+	LogResponseViolations ResponseValidationMode = iota
+	// FailOnResponseViolations logs the violations and fails with a 500 HTTPError
+	FailOnResponseViolations
+)
+
+// NewResponseJSONSchemaValidator returns an interceptor that validates the output of the Lambda function against the
+// given JSON schema, or an error if the schema is invalid. The body of API Gateway and Lambda Function URL responses
+// is validated, unless their status code reports an error (4xx or 5xx). Other outputs are validated as they are.
+//
+// The fields that violated the schema are always logged. With FailOnResponseViolations (e.g. in test environments),
+// the Lambda function fails with a 500 HTTPError wrapping a ValidationError as well, which
+// CreateAPIGatewayProxyResponse can turn into a response when provided before (outside) this interceptor
+func NewResponseJSONSchemaValidator(schema string, mode ResponseValidationMode) (gointercept.Interceptor, error) {
+	rs, err := compileJSONSchema(schema)
+	if err != nil {
+		return gointercept.Interceptor{}, err
+	}
+
+	return gointercept.Interceptor{
+		After: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			body, ok, err := getResponseBody(payload)
+			if err != nil || !ok {
+				return payload, err
+			}
+
+			errs, err := rs.ValidateBytes(ctx, body)
+			if err != nil {
+				return payload, err
+			}
+			if len(errs) == 0 {
+				return payload, nil
+			}
+
+			validationError := newSchemaValidationError(errs, "")
+			log.Printf("Response does not match its JSON schema - %s\n", validationError)
+			if mode == FailOnResponseViolations {
+				return payload, &HTTPError{
+					StatusCode: http.StatusInternalServerError,
+					StatusText: http.StatusText(http.StatusInternalServerError),
+					Err:        validationError,
+				}
+			}
+
+			return payload, nil
+		},
+	}, nil
+}
+
+// getResponseBody returns the JSON document to validate for the given output, and whether it must be validated
+func getResponseBody(payload interface{}) ([]byte, bool, error) {
+	var statusCode int
+	var body string
+	var isBase64Encoded bool
+
+	switch response := payload.(type) {
+	case events.APIGatewayProxyResponse:
+		statusCode, body, isBase64Encoded = response.StatusCode, response.Body, response.IsBase64Encoded
+	case events.LambdaFunctionURLResponse:
+		statusCode, body, isBase64Encoded = response.StatusCode, response.Body, response.IsBase64Encoded
+	default:
+		b, err := internal.GetBytes(payload)
+		return b, true, err
+	}
+
+	if statusCode >= http.StatusBadRequest {
+		return nil, false, nil
+	}
+	if !isBase64Encoded {
+		return []byte(body), true, nil
+	}
+
+	b, err := base64.StdEncoding.DecodeString(body)
+	return b, true, err
+}
@@ -0,0 +1,146 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// AnyMethod can be used as the method of a Route to match requests regardless of their HTTP method
+const AnyMethod = "ANY"
+
+// Route maps an HTTP method and a path template to a handler. Path templates follow the API Gateway syntax: segments
+// such as '{id}' match a single path segment and a trailing '{proxy+}' segment matches the rest of the path.
+//
+// Handler is usually a Lambda function already wrapped with its own interceptors (gointercept.This(...).With(...))
+type Route struct {
+	Method  string
+	Path    string
+	Handler gointercept.LambdaHandler
+}
+
+type pathParametersKey struct{}
+
+// PathParameters returns the path parameters of the request dispatched by the Router (those extracted from the request
+// path merged into the request's own PathParameters), if any
+func PathParameters(ctx context.Context) map[string]string {
+	if parameters, ok := ctx.Value(pathParametersKey{}).(map[string]string); ok {
+		return parameters
+	}
+
+	return nil
+}
+
+type compiledRoute struct {
+	Route
+	segments []string
+}
+
+// Router dispatches API Gateway (HTTP) requests to the handler of the first route that matches the request's method
+// and path. The path parameters found are merged into the request's PathParameters and made available through the
+// context (see PathParameters).
+//
+// Requests that don't match any route fail with a 404 HTTPError. Requests whose path matches but whose method does not
+// fail with a 405 HTTPError that lists the allowed methods in the 'Allow' header. Use CreateAPIGatewayProxyResponse
+// around the router to turn these errors into responses
+func Router(routes ...Route) gointercept.LambdaHandler {
+	compiledRoutes := make([]compiledRoute, len(routes))
+	for i, route := range routes {
+		compiledRoutes[i] = compiledRoute{Route: route, segments: splitPath(route.Path)}
+	}
+
+	return func(ctx context.Context, payload interface{}) (interface{}, error) {
+		request, err := getAPIGatewayProxyRequest(payload)
+		if err != nil {
+			return payload, err
+		}
+
+		allowed := make(map[string]bool)
+		for _, route := range compiledRoutes {
+			parameters, ok := matchPath(route.segments, splitPath(request.Path))
+			if !ok {
+				continue
+			}
+			if route.Method != AnyMethod && !strings.EqualFold(route.Method, request.HTTPMethod) {
+				allowed[strings.ToUpper(route.Method)] = true
+				continue
+			}
+
+			pathParameters := make(map[string]string, len(request.PathParameters)+len(parameters))
+			for k, v := range request.PathParameters {
+				pathParameters[k] = v
+			}
+			for k, v := range parameters {
+				pathParameters[k] = v
+			}
+			request.PathParameters = pathParameters
+
+			return route.Handler(context.WithValue(ctx, pathParametersKey{}, pathParameters), request)
+		}
+
+		if len(allowed) > 0 {
+			methods := make([]string, 0, len(allowed))
+			for method := range allowed {
+				methods = append(methods, method)
+			}
+			sort.Strings(methods)
+			return payload, &HTTPError{
+				StatusCode: http.StatusMethodNotAllowed,
+				StatusText: fmt.Sprintf("method %s not allowed for %s", request.HTTPMethod, request.Path),
+				Headers:    map[string]string{"Allow": strings.Join(methods, ", ")},
+			}
+		}
+
+		return payload, &HTTPError{StatusCode: http.StatusNotFound, StatusText: fmt.Sprintf("%s not found", request.Path)}
+	}
+}
+
+func getAPIGatewayProxyRequest(payload interface{}) (events.APIGatewayProxyRequest, error) {
+	if request, ok := payload.(events.APIGatewayProxyRequest); ok {
+		return request, nil
+	}
+
+	var request events.APIGatewayProxyRequest
+	if err := internal.Decode(payload, &request); err != nil {
+		return request, fmt.Errorf("payload is not an API Gateway request - %w", err)
+	}
+
+	return request, nil
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+func matchPath(template []string, path []string) (map[string]string, bool) {
+	parameters := make(map[string]string)
+	for i, segment := range template {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "+}") {
+			if i >= len(path) {
+				return nil, false
+			}
+			parameters[segment[1:len(segment)-2]] = strings.Join(path[i:], "/")
+			return parameters, true
+		}
+		if i >= len(path) {
+			return nil, false
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			parameters[segment[1:len(segment)-1]] = path[i]
+		} else if segment != path[i] {
+			return nil, false
+		}
+	}
+
+	return parameters, len(template) == len(path)
+}
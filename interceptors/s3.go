@@ -0,0 +1,121 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"net/url"
+	"time"
+)
+
+// S3Object represents a normalized S3 event record. Its key is already URL-decoded (e.g. '+' is turned into a space)
+type S3Object struct {
+	EventName string    `json:"eventName"`
+	EventTime time.Time `json:"eventTime"`
+	Region    string    `json:"region"`
+	Bucket    string    `json:"bucket"`
+	Key       string    `json:"key"`
+	Size      int64     `json:"size"`
+	ETag      string    `json:"eTag"`
+	VersionID string    `json:"versionId"`
+}
+
+// ParseS3Event normalizes the records of the S3 event received by the Lambda function into S3Object instances.
+//
+// If forEachObject is false, the Lambda function receives all the objects at once ([]S3Object). Otherwise, the Lambda
+// function is called once per object and its responses are collected in a slice. Failed objects do not stop the
+// processing of the remaining ones; their errors are aggregated in a BatchError instead
+func ParseS3Event(forEachObject bool) gointercept.Interceptor {
+	interceptor := gointercept.Interceptor{
+		Before: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			event, err := getS3Event(payload)
+			if err != nil {
+				return payload, err
+			}
+			if forEachObject {
+				return event, nil
+			}
+
+			var batchError BatchError
+			objects := make([]S3Object, 0, len(event.Records))
+			for _, record := range event.Records {
+				object, err := normalizeS3Record(record)
+				if err != nil {
+					batchError.add(s3RecordID(record), err)
+					continue
+				}
+				objects = append(objects, object)
+			}
+
+			if err := batchError.errorOrNil(); err != nil {
+				return payload, err
+			}
+
+			return objects, nil
+		},
+	}
+
+	if forEachObject {
+		interceptor.Around = func(next gointercept.LambdaHandler) gointercept.LambdaHandler {
+			return func(ctx context.Context, payload interface{}) (interface{}, error) {
+				event := payload.(events.S3Event)
+
+				var batchError BatchError
+				responses := make([]interface{}, len(event.Records))
+				for i, record := range event.Records {
+					object, err := normalizeS3Record(record)
+					if err != nil {
+						batchError.add(s3RecordID(record), err)
+						continue
+					}
+
+					responses[i], err = next(ctx, object)
+					if err != nil {
+						batchError.add(s3RecordID(record), err)
+					}
+				}
+
+				return responses, batchError.errorOrNil()
+			}
+		}
+	}
+
+	return interceptor
+}
+
+func getS3Event(payload interface{}) (events.S3Event, error) {
+	if event, ok := payload.(events.S3Event); ok {
+		return event, nil
+	}
+
+	var event events.S3Event
+	if err := internal.Decode(payload, &event); err != nil {
+		return event, fmt.Errorf("payload is not an S3 event - %w", err)
+	}
+
+	return event, nil
+}
+
+func normalizeS3Record(record events.S3EventRecord) (S3Object, error) {
+	key, err := url.QueryUnescape(record.S3.Object.Key)
+	if err != nil {
+		return S3Object{}, fmt.Errorf("can't decode key %q - %w", record.S3.Object.Key, err)
+	}
+
+	return S3Object{
+		EventName: record.EventName,
+		EventTime: record.EventTime,
+		Region:    record.AWSRegion,
+		Bucket:    record.S3.Bucket.Name,
+		Key:       key,
+		Size:      record.S3.Object.Size,
+		ETag:      record.S3.Object.ETag,
+		VersionID: record.S3.Object.VersionID,
+	}, nil
+}
+
+func s3RecordID(record events.S3EventRecord) string {
+	return record.S3.Bucket.Name + "/" + record.S3.Object.Key
+}
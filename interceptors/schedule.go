@@ -0,0 +1,146 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"sync"
+	"time"
+)
+
+// ErrNotScheduledEvent is returned by HandleScheduledEvent when the payload is not a scheduled event
+var ErrNotScheduledEvent = errors.New("payload is not a scheduled event")
+
+// DeduplicationStore keeps track of the events already processed. Claim records the given event ID and returns false
+// if it was already recorded, which must be done atomically when the store is shared. Release removes an event ID so
+// the event can be processed again (e.g. after a failure)
+type DeduplicationStore interface {
+	Claim(ctx context.Context, id string) (bool, error)
+	Release(ctx context.Context, id string) error
+}
+
+// MemoryDeduplicationStore is a DeduplicationStore that keeps event IDs in memory for the given time, which must be
+// positive so the store doesn't grow for the lifetime of the Lambda function instance. It only detects duplicates
+// delivered to the same instance, so stores backed by a database (e.g. DynamoDB conditional writes) should be preferred
+type MemoryDeduplicationStore struct {
+	TTL time.Duration
+
+	mu  sync.Mutex
+	ids map[string]time.Time
+}
+
+// Claim records the given event ID and returns false if it was already recorded and has not expired. It fails if the
+// store's TTL is not positive
+func (s *MemoryDeduplicationStore) Claim(ctx context.Context, id string) (bool, error) {
+	if s.TTL <= 0 {
+		return false, fmt.Errorf("MemoryDeduplicationStore needs a positive TTL, got %s", s.TTL)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.ids == nil {
+		s.ids = make(map[string]time.Time)
+	}
+	for k, expiration := range s.ids {
+		if now.After(expiration) {
+			delete(s.ids, k)
+		}
+	}
+	if _, ok := s.ids[id]; ok {
+		return false, nil
+	}
+
+	s.ids[id] = now.Add(s.TTL)
+	return true, nil
+}
+
+// Release removes the given event ID
+func (s *MemoryDeduplicationStore) Release(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.ids, id)
+	return nil
+}
+
+type scheduledEventKey struct{}
+
+// GetScheduledEvent returns the scheduled event added to the context by HandleScheduledEvent, if any
+func GetScheduledEvent(ctx context.Context) (events.CloudWatchEvent, bool) {
+	event, ok := ctx.Value(scheduledEventKey{}).(events.CloudWatchEvent)
+	return event, ok
+}
+
+// ScheduledTime returns the time the scheduled event in the context was scheduled for, if any
+func ScheduledTime(ctx context.Context) (time.Time, bool) {
+	event, ok := GetScheduledEvent(ctx)
+	return event.Time, ok
+}
+
+// ScheduledEventDetail decodes the detail of the scheduled event in the context (e.g. the constant input configured
+// for the rule) into a new value of type T. An empty detail results in the zero value of T
+func ScheduledEventDetail[T any](ctx context.Context) (T, error) {
+	var detail T
+	event, ok := GetScheduledEvent(ctx)
+	if !ok {
+		return detail, ErrNotScheduledEvent
+	}
+	if len(event.Detail) == 0 {
+		return detail, nil
+	}
+	if err := json.Unmarshal(event.Detail, &detail); err != nil {
+		return detail, fmt.Errorf("can't decode the detail of scheduled event %s - %w", event.ID, err)
+	}
+
+	return detail, nil
+}
+
+// HandleScheduledEvent validates that the payload is an EventBridge (CloudWatch Events) scheduled event and makes it
+// available through the context (see GetScheduledEvent, ScheduledTime, and ScheduledEventDetail). Other payloads fail
+// with ErrNotScheduledEvent.
+//
+// If a DeduplicationStore is given, events already claimed by a previous invocation are skipped (the rest of the
+// chain is not executed and nil is returned), since EventBridge may deliver the same event more than once. Events
+// whose processing fails are released so they can be retried
+func HandleScheduledEvent(store DeduplicationStore) gointercept.Interceptor {
+	return gointercept.Interceptor{
+		Around: func(next gointercept.LambdaHandler) gointercept.LambdaHandler {
+			return func(ctx context.Context, payload interface{}) (interface{}, error) {
+				event, ok := payload.(events.CloudWatchEvent)
+				if !ok {
+					if err := internal.Decode(payload, &event); err != nil {
+						return payload, fmt.Errorf("%w - %v", ErrNotScheduledEvent, err)
+					}
+				}
+				if event.Source != "aws.events" || event.DetailType != "Scheduled Event" || event.ID == "" {
+					return payload, ErrNotScheduledEvent
+				}
+
+				if store != nil {
+					claimed, err := store.Claim(ctx, event.ID)
+					if err != nil {
+						return payload, fmt.Errorf("can't claim scheduled event %s - %w", event.ID, err)
+					}
+					if !claimed {
+						return nil, nil
+					}
+				}
+
+				response, err := next(context.WithValue(ctx, scheduledEventKey{}, event), payload)
+				if err != nil && store != nil {
+					if releaseErr := store.Release(ctx, event.ID); releaseErr != nil {
+						return response, fmt.Errorf("%w (can't release scheduled event %s - %v)", err, event.ID, releaseErr)
+					}
+				}
+
+				return response, err
+			}
+		},
+	}
+}
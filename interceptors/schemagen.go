@@ -0,0 +1,348 @@
+package interceptors
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jpcedenog/gointercept"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+type schemaGenerator struct {
+	root  reflect.Type
+	names map[reflect.Type]string
+	defs  map[string]interface{}
+}
+
+// GenerateJSONSchema derives a JSON schema from the type of the given value (usually a pointer to a struct), so it
+// doesn't have to be written and kept in sync by hand. Properties are named after the 'json' tags of the struct fields
+// and the rules in their 'validate' and 'pattern' tags (see ValidateStruct) become the equivalent schema keywords
+// (e.g. 'required', 'minimum', 'maxLength', 'enum', or 'format'). Nested struct types are defined once in '$defs' and
+// referenced with '$ref', so recursive types are supported as well.
+//
+// The rules mean the same as in ValidateStruct: 'required' fields must be present and, unless they are pointers, must
+// not have their zero value (e.g. empty strings get 'minLength: 1'), while optional pointer fields accept null. The
+// only differences are that the zero value of required struct fields is rejected by ValidateStruct alone, and that
+// the schema doesn't check optional fields absent from the payload, so optional fields should be pointers
+func GenerateJSONSchema(v interface{}) (string, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return "", fmt.Errorf("can't generate a JSON schema for nil")
+	}
+
+	g := schemaGenerator{root: t, names: make(map[reflect.Type]string), defs: make(map[string]interface{})}
+	schema, err := g.schemaFor(t, true)
+	if err != nil {
+		return "", err
+	}
+	if len(g.defs) > 0 {
+		schema["$defs"] = g.defs
+	}
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// NewBodyJSONSchemaValidatorFor returns an interceptor that validates the given payload (in JSON format) against the
+// JSON schema generated from the type of the given value (see GenerateJSONSchema and NewBodyJSONSchemaValidator)
+func NewBodyJSONSchemaValidatorFor(v interface{}) (gointercept.Interceptor, error) {
+	schema, err := GenerateJSONSchema(v)
+	if err != nil {
+		return gointercept.Interceptor{}, err
+	}
+
+	return NewBodyJSONSchemaValidator(schema)
+}
+
+func (g *schemaGenerator) schemaFor(t reflect.Type, inline bool) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	case t == rawMessageType:
+		return map[string]interface{}{}, nil
+	case reflect.PtrTo(t).Implements(textUnmarshalerType):
+		return map[string]interface{}{"type": "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := g.schemaFor(t.Elem(), false)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("can't generate a JSON schema for %s - map keys must be strings", t)
+		}
+		values, err := g.schemaFor(t.Elem(), false)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		if inline || t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.structRef(t)
+	}
+
+	return nil, fmt.Errorf("can't generate a JSON schema for %s", t)
+}
+
+// structRef returns a reference to the definition of the given named struct type, adding it to '$defs' if needed
+func (g *schemaGenerator) structRef(t reflect.Type) (map[string]interface{}, error) {
+	if t == g.root {
+		return map[string]interface{}{"$ref": "#"}, nil
+	}
+
+	name, ok := g.names[t]
+	if !ok {
+		name = t.Name()
+		for i := 2; g.defs[name] != nil; i++ {
+			name = t.Name() + strconv.Itoa(i)
+		}
+		g.names[t] = name
+		g.defs[name] = map[string]interface{}{}
+
+		schema, err := g.structSchema(t)
+		if err != nil {
+			return nil, err
+		}
+		g.defs[name] = schema
+	}
+
+	return map[string]interface{}{"$ref": "#/$defs/" + name}, nil
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) (map[string]interface{}, error) {
+	properties := make(map[string]interface{})
+	var required []string
+	if err := g.addProperties(t, properties, &required); err != nil {
+		return nil, err
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema, nil
+}
+
+// addProperties adds the schema of each field of the given struct type to properties. The fields of embedded structs
+// are added as well, as they are encoded by encoding/json
+func (g *schemaGenerator) addProperties(t reflect.Type, properties map[string]interface{}, required *[]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && field.Tag.Get("json") == "" && fieldType.Kind() == reflect.Struct {
+			if err := g.addProperties(fieldType, properties, required); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name, ok := bindingName(field, "json")
+		if !ok {
+			continue
+		}
+		property, err := g.schemaFor(field.Type, false)
+		if err != nil {
+			return fmt.Errorf("field %s - %w", field.Name, err)
+		}
+		isRequired, err := applyValidationRules(property, fieldType, field.Tag)
+		if err != nil {
+			return fmt.Errorf("field %s - %w", field.Name, err)
+		}
+		if isRequired {
+			*required = append(*required, name)
+			if field.Type.Kind() != reflect.Ptr {
+				rejectZeroValue(property, fieldType)
+			}
+		} else if field.Type.Kind() == reflect.Ptr {
+			property = allowNull(property)
+		}
+		properties[name] = property
+	}
+
+	return nil
+}
+
+// applyValidationRules adds the schema keywords equivalent to the rules in the 'validate' and 'pattern' tags to the
+// given schema. It returns whether the field is required
+func applyValidationRules(schema map[string]interface{}, t reflect.Type, tag reflect.StructTag) (bool, error) {
+	var required bool
+	for _, rule := range strings.Split(tag.Get("validate"), ",") {
+		name, arg := strings.TrimSpace(rule), ""
+		if i := strings.Index(name, "="); i >= 0 {
+			name, arg = name[:i], name[i+1:]
+		}
+
+		switch name {
+		case "":
+		case "required":
+			required = true
+		case "min", "max", "len":
+			bound, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return false, fmt.Errorf("invalid %s bound %q", name, arg)
+			}
+			keywords, err := boundKeywords(t, name)
+			if err != nil {
+				return false, err
+			}
+			for _, keyword := range keywords {
+				schema[keyword] = bound
+			}
+		case "enum":
+			var values []interface{}
+			for _, v := range strings.Split(arg, "|") {
+				value, err := enumValue(t, v)
+				if err != nil {
+					return false, err
+				}
+				values = append(values, value)
+			}
+			schema["enum"] = values
+		case "email":
+			if t.Kind() != reflect.String {
+				return false, fmt.Errorf("rule %s can't be applied to %s", name, t)
+			}
+			schema["format"] = "email"
+		case "uuid":
+			if t.Kind() != reflect.String {
+				return false, fmt.Errorf("rule %s can't be applied to %s", name, t)
+			}
+			schema["format"] = "uuid"
+			schema["pattern"] = uuidPattern.String()
+		default:
+			return false, fmt.Errorf("unknown validation rule %q", name)
+		}
+	}
+
+	if pattern, ok := tag.Lookup("pattern"); ok {
+		if t.Kind() != reflect.String {
+			return false, fmt.Errorf("pattern can't be applied to %s", t)
+		}
+		schema["pattern"] = pattern
+	}
+
+	return required, nil
+}
+
+// rejectZeroValue adds the schema keywords that reject the zero value of the given type, as the 'required' rule of
+// ValidateStruct does for fields that are not pointers (e.g. empty strings and slices, 0, or false)
+func rejectZeroValue(schema map[string]interface{}, t reflect.Type) {
+	atLeastOne := func(keyword string) {
+		if n, ok := schema[keyword].(float64); !ok || n < 1 {
+			schema[keyword] = 1
+		}
+	}
+
+	switch {
+	case t.Kind() == reflect.String, schema["contentEncoding"] == "base64":
+		atLeastOne("minLength")
+	case t.Kind() == reflect.Slice:
+		atLeastOne("minItems")
+	case t.Kind() == reflect.Map:
+		atLeastOne("minProperties")
+	case t.Kind() == reflect.Bool:
+		schema["const"] = true
+	case t.Kind() == reflect.Interface:
+		schema["not"] = map[string]interface{}{"type": "null"}
+	case schema["type"] == "integer" || schema["type"] == "number":
+		schema["not"] = map[string]interface{}{"const": 0}
+	}
+}
+
+// allowNull makes the given schema accept null as well, as optional pointer fields are nil when their value is null
+func allowNull(schema map[string]interface{}) map[string]interface{} {
+	if _, ok := schema["$ref"]; ok {
+		return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+	}
+	if schemaType, ok := schema["type"].(string); ok {
+		schema["type"] = []string{schemaType, "null"}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		schema["enum"] = append(enum, nil)
+	}
+
+	return schema
+}
+
+// boundKeywords returns the schema keywords that enforce the given bound rule (min, max, or len) for the given type
+func boundKeywords(t reflect.Type, rule string) ([]string, error) {
+	var min, max string
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		min, max = "minimum", "maximum"
+	case reflect.String:
+		min, max = "minLength", "maxLength"
+	case reflect.Slice, reflect.Array:
+		min, max = "minItems", "maxItems"
+	case reflect.Map:
+		min, max = "minProperties", "maxProperties"
+	default:
+		return nil, fmt.Errorf("rule %s can't be applied to %s", rule, t)
+	}
+
+	switch rule {
+	case "min":
+		return []string{min}, nil
+	case "max":
+		return []string{max}, nil
+	}
+
+	return []string{min, max}, nil
+}
+
+func enumValue(t reflect.Type, value string) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	}
+
+	return value, nil
+}
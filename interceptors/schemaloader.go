@@ -0,0 +1,305 @@
+package interceptors
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jpcedenog/gointercept"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var unsafeDefinitionChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// SchemaRegistry holds JSON schemas keyed by their '$id', so schemas can reference each other by '$id' (absolute or
+// relative to the '$id' of the referencing schema)
+type SchemaRegistry struct {
+	schemas map[string]interface{}
+}
+
+// NewSchemaRegistry returns a SchemaRegistry with the given schemas, or an error if a schema is not valid JSON or
+// has no '$id'
+func NewSchemaRegistry(schemas ...string) (*SchemaRegistry, error) {
+	registry := &SchemaRegistry{schemas: make(map[string]interface{})}
+	for _, schema := range schemas {
+		if err := registry.Add(schema); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+// LoadSchemaRegistry returns a SchemaRegistry with the schemas stored in the files of the given file system (e.g. an
+// embed.FS) whose names match the given pattern (see fs.Glob), or an error if a schema is not valid JSON or has no
+// '$id'
+func LoadSchemaRegistry(fsys fs.FS, pattern string) (*SchemaRegistry, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &SchemaRegistry{schemas: make(map[string]interface{})}
+	for _, name := range names {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := registry.Add(string(b)); err != nil {
+			return nil, fmt.Errorf("%s - %w", name, err)
+		}
+	}
+
+	return registry, nil
+}
+
+// Add adds the given schema to the registry, replacing the schema with the same '$id' if any
+func (r *SchemaRegistry) Add(schema string) error {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &doc); err != nil {
+		return fmt.Errorf("invalid JSON schema - %w", err)
+	}
+	id, _ := doc["$id"].(string)
+	if id == "" {
+		return fmt.Errorf("invalid JSON schema - schemas added to a registry must have an $id")
+	}
+
+	r.schemas[strings.TrimSuffix(id, "#")] = doc
+	return nil
+}
+
+// Schema returns the schema with the given '$id' as a single JSON schema, with the schemas it references, directly or
+// not, embedded in its '$defs'
+func (r *SchemaRegistry) Schema(id string) (string, error) {
+	id = strings.TrimSuffix(id, "#")
+	doc, ok := r.schemas[id]
+	if !ok {
+		return "", fmt.Errorf("schema %s not found", id)
+	}
+
+	return bundleSchema(id, copySchema(doc), func(base, address string) (string, interface{}, error) {
+		key := address
+		if baseURL, err := url.Parse(base); err == nil && baseURL.IsAbs() {
+			if ref, err := url.Parse(address); err == nil {
+				key = baseURL.ResolveReference(ref).String()
+			}
+		}
+		doc, ok := r.schemas[key]
+		if !ok {
+			return "", nil, fmt.Errorf("schema %s not found", key)
+		}
+		return key, copySchema(doc), nil
+	})
+}
+
+// LoadJSONSchema reads the schema stored in the given file of the given file system (e.g. an embed.FS) and returns it
+// as a single JSON schema, with the files it references, directly or not, embedded in its '$defs'. References to other
+// files are relative to the referencing file (e.g. 'common.json#/$defs/address')
+func LoadJSONSchema(fsys fs.FS, name string) (string, error) {
+	load := func(base, address string) (string, interface{}, error) {
+		if u, err := url.Parse(address); err != nil || u.IsAbs() {
+			return "", nil, fmt.Errorf("reference %s must be a path relative to %s", address, base)
+		}
+		key := path.Clean(path.Join(path.Dir(base), address))
+		b, err := fs.ReadFile(fsys, key)
+		if err != nil {
+			return "", nil, err
+		}
+		var doc interface{}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return "", nil, fmt.Errorf("invalid JSON schema %s - %w", key, err)
+		}
+		return key, doc, nil
+	}
+
+	name = path.Clean(name)
+	_, doc, err := load(".", name)
+	if err != nil {
+		return "", err
+	}
+
+	return bundleSchema(name, doc, load)
+}
+
+// NewBodyJSONSchemaValidatorFromFS returns an interceptor that validates the given payload (in JSON format) against
+// the schema stored in the given file, and the files it references (see LoadJSONSchema and NewBodyJSONSchemaValidator)
+func NewBodyJSONSchemaValidatorFromFS(fsys fs.FS, name string) (gointercept.Interceptor, error) {
+	schema, err := LoadJSONSchema(fsys, name)
+	if err != nil {
+		return gointercept.Interceptor{}, err
+	}
+
+	return NewBodyJSONSchemaValidator(schema)
+}
+
+// NewBodyJSONSchemaValidatorFromRegistry returns an interceptor that validates the given payload (in JSON format)
+// against the schema of the registry with the given '$id', and the schemas it references (see SchemaRegistry and
+// NewBodyJSONSchemaValidator)
+func NewBodyJSONSchemaValidatorFromRegistry(registry *SchemaRegistry, id string) (gointercept.Interceptor, error) {
+	schema, err := registry.Schema(id)
+	if err != nil {
+		return gointercept.Interceptor{}, err
+	}
+
+	return NewBodyJSONSchemaValidator(schema)
+}
+
+// copySchema returns a deep copy of the given schema, so bundles don't modify the schemas of a registry
+func copySchema(doc interface{}) interface{} {
+	b, _ := json.Marshal(doc)
+	var copied interface{}
+	_ = json.Unmarshal(b, &copied)
+
+	return copied
+}
+
+// schemaLoader returns the key and the content of the schema referenced by the given address from the schema with
+// the given key
+type schemaLoader func(base, address string) (string, interface{}, error)
+
+// schemaBundle embeds the schemas referenced by a root schema in its '$defs', so all references can be resolved
+// locally, once, when the bundle is created
+type schemaBundle struct {
+	root  string
+	load  schemaLoader
+	names map[string]string
+	defs  map[string]interface{}
+}
+
+func bundleSchema(key string, doc interface{}, load schemaLoader) (string, error) {
+	b := schemaBundle{root: key, load: load, names: make(map[string]string), defs: make(map[string]interface{})}
+	if root, ok := doc.(map[string]interface{}); ok {
+		if defs, ok := root["$defs"].(map[string]interface{}); ok {
+			for name, def := range defs {
+				b.defs[name] = def
+			}
+		}
+	}
+	if err := b.resolve(key, doc); err != nil {
+		return "", err
+	}
+
+	if len(b.defs) > 0 {
+		root, ok := doc.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("invalid JSON schema %s - schemas with references must be objects", key)
+		}
+		root["$defs"] = b.defs
+	}
+
+	bundled, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+
+	return string(bundled), nil
+}
+
+// resolve rewrites the references found in the given schema, part of the schema with the given key, so they point to
+// the root schema or to the schemas embedded in its '$defs'. Keys are walked in order so bundles are reproducible
+func (b *schemaBundle) resolve(key string, schema interface{}) error {
+	switch v := schema.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			switch k {
+			case "enum", "const", "default", "examples":
+				// Keyword values are instances, not schemas, so they can't contain references
+			case "$ref":
+				ref, ok := v[k].(string)
+				if !ok {
+					continue
+				}
+				resolved, err := b.resolveRef(key, ref)
+				if err != nil {
+					return err
+				}
+				v[k] = resolved
+			case "properties", "patternProperties", "dependentSchemas", "$defs", "definitions":
+				// The keys of these keywords are names (e.g. of properties), and their values are schemas
+				schemas, ok := v[k].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for _, name := range sortedKeys(schemas) {
+					if err := b.resolve(key, schemas[name]); err != nil {
+						return err
+					}
+				}
+			default:
+				if err := b.resolve(key, v[k]); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if err := b.resolve(key, child); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *schemaBundle) resolveRef(key, ref string) (string, error) {
+	address, fragment := ref, ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		address, fragment = ref[:i], ref[i+1:]
+	}
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return "", fmt.Errorf("can't resolve %s - only JSON pointer fragments are supported", ref)
+	}
+
+	target := key
+	if address != "" {
+		loadedKey, doc, err := b.load(key, address)
+		if err != nil {
+			return "", fmt.Errorf("can't resolve %s - %w", ref, err)
+		}
+		target = loadedKey
+		if _, ok := b.names[target]; !ok && target != b.root {
+			if err := b.embed(target, doc); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if target == b.root {
+		return "#" + fragment, nil
+	}
+
+	return "#/$defs/" + b.names[target] + fragment, nil
+}
+
+// embed adds the given schema to the '$defs' of the root schema. Its '$id' and '$schema' are removed, so the
+// references rewritten by the bundle are resolved against the root schema
+func (b *schemaBundle) embed(key string, doc interface{}) error {
+	base := unsafeDefinitionChars.ReplaceAllString(strings.TrimSuffix(path.Base(key), ".json"), "_")
+	name := base
+	for i := 2; b.defs[name] != nil; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	b.names[key] = name
+	b.defs[name] = doc
+
+	if object, ok := doc.(map[string]interface{}); ok {
+		delete(object, "$id")
+		delete(object, "$schema")
+	}
+
+	return b.resolve(key, doc)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
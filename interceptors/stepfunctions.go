@@ -0,0 +1,141 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrorTypeMapping maps an error (usually a sentinel error, matched with errors.Is) to the error type reported in
+// the Lambda function's error response
+type ErrorTypeMapping struct {
+	Target error
+	Type   string
+}
+
+// MapErrorTypes reports the errors raised by the Lambda function with stable error types (the 'errorType' of the
+// Lambda error response), so Step Functions state machines can retry and catch them by name. The type of the first
+// mapping whose Target matches the error (errors.Is), even if wrapped, is used. HTTPErrors without a mapping are
+// given a type based on their status code (e.g. 'NotFound'). Other errors are left unchanged
+func MapErrorTypes(mappings ...ErrorTypeMapping) gointercept.Interceptor {
+	return gointercept.Interceptor{
+		OnError: func(ctx context.Context, payload interface{}, err error) (interface{}, error) {
+			for _, mapping := range mappings {
+				if errors.Is(err, mapping.Target) {
+					return payload, messages.InvokeResponse_Error{Type: mapping.Type, Message: err.Error()}
+				}
+			}
+
+			var httpError *HTTPError
+			if errors.As(err, &httpError) {
+				return payload, messages.InvokeResponse_Error{Type: httpErrorType(httpError.StatusCode), Message: httpError.StatusText}
+			}
+
+			return payload, err
+		},
+	}
+}
+
+// ProjectPaths selects the part of the payload given to the rest of the chain (inputPath) and the part of the
+// Lambda function's output that is returned (outputPath), like the InputPath and OutputPath fields of Step Functions
+// states. Paths use the JSONPath subset supported by Step Functions: '$' (the whole value), fields ('$.order.id'),
+// and array indexes ('$.items[0]'). Empty paths leave the payload or output unchanged
+func ProjectPaths(inputPath, outputPath string) gointercept.Interceptor {
+	input, inputErr := parsePath(inputPath)
+	output, outputErr := parsePath(outputPath)
+
+	return gointercept.Interceptor{
+		Before: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			if inputErr != nil {
+				return payload, inputErr
+			}
+			return selectPath(payload, input)
+		},
+		After: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			if outputErr != nil {
+				return payload, outputErr
+			}
+			return selectPath(payload, output)
+		},
+	}
+}
+
+// parsePath splits the given JSONPath into field names and array indexes
+func parsePath(path string) ([]interface{}, error) {
+	if path == "" || path == "$" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "$.") && !strings.HasPrefix(path, "$[") {
+		return nil, fmt.Errorf("invalid path %q - paths must start with '$'", path)
+	}
+
+	var tokens []interface{}
+	for _, field := range strings.Split(strings.TrimPrefix(path[1:], "."), ".") {
+		name := field
+		var indexes []interface{}
+		if i := strings.Index(field, "["); i >= 0 {
+			name = field[:i]
+			for _, index := range strings.Split(strings.TrimSuffix(field[i+1:], "]"), "][") {
+				n, err := strconv.Atoi(index)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid path %q - bad array index %q", path, index)
+				}
+				indexes = append(indexes, n)
+			}
+		}
+		if name != "" {
+			tokens = append(tokens, name)
+		} else if len(tokens) > 0 || len(indexes) == 0 {
+			return nil, fmt.Errorf("invalid path %q - empty field name", path)
+		}
+		tokens = append(tokens, indexes...)
+	}
+
+	return tokens, nil
+}
+
+func selectPath(payload interface{}, tokens []interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return payload, nil
+	}
+
+	var value interface{}
+	if err := internal.Decode(payload, &value); err != nil {
+		return payload, err
+	}
+
+	for _, token := range tokens {
+		switch t := token.(type) {
+		case string:
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return payload, fmt.Errorf("can't select field %q of a non-object value", t)
+			}
+			if value, ok = object[t]; !ok {
+				return payload, fmt.Errorf("field %q not found", t)
+			}
+		case int:
+			array, ok := value.([]interface{})
+			if !ok || t >= len(array) {
+				return payload, fmt.Errorf("index %d not found", t)
+			}
+			value = array[t]
+		}
+	}
+
+	return value, nil
+}
+
+func httpErrorType(statusCode int) string {
+	if text := http.StatusText(statusCode); text != "" {
+		return strings.NewReplacer(" ", "", "-", "", "'", "").Replace(text)
+	}
+
+	return "HTTPError" + strconv.Itoa(statusCode)
+}
@@ -0,0 +1,266 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jpcedenog/gointercept"
+	"net/http"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var patterns sync.Map
+
+// FieldViolation describes why the value of a field is invalid. Field is the path to the field (e.g. 'items[0].name')
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError contains all the violations found while validating a value
+type ValidationError struct {
+	Violations []FieldViolation `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Field + ": " + violation.Message
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// toHTTPError converts the ValidationError into an HTTPError whose body is the JSON encoding of the violations
+func (e *ValidationError) toHTTPError(statusCode int) *HTTPError {
+	body, err := json.Marshal(e)
+	if err != nil {
+		body = []byte(e.Error())
+	}
+
+	return &HTTPError{
+		StatusCode: statusCode,
+		StatusText: string(body),
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Err:        e,
+	}
+}
+
+// ValidateStruct validates the payload, usually the value created by ParseBody, against the rules declared in the
+// 'validate' tags of its fields (e.g. `validate:"required,min=1,max=10"`). The supported rules are:
+//
+//	required     the value must not be the zero value (or nil, or empty)
+//	min=N, max=N bounds of numbers, or of the length of strings, slices, and maps
+//	len=N        exact length of strings, slices, and maps
+//	enum=a|b|c   the value must be one of the given values
+//	email, uuid  the value must be an email address or a UUID
+//
+// Regular expressions are given in a separate 'pattern' tag (e.g. `pattern:"^[a-z]+$"`). The 'email', 'uuid', and
+// 'pattern' rules only apply to strings. Only 'required' treats zero values as missing: the other rules are checked
+// for zero values as well (e.g. 'min=1' rejects 0), so optional fields should be pointers, whose rules are only checked
+// when they are not nil. Nested structs, and slices of structs, are validated as well.
+//
+// All violations, with the path to each field (based on their 'json' tags), are reported together in a 422 HTTPError
+// whose body is a JSON document, wrapping a ValidationError
+func ValidateStruct() gointercept.Interceptor {
+	return gointercept.Interceptor{
+		Before: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			value := reflect.ValueOf(payload)
+			for value.Kind() == reflect.Ptr && !value.IsNil() {
+				value = value.Elem()
+			}
+			if value.Kind() != reflect.Struct {
+				return payload, fmt.Errorf("can't validate %T - payload must be a struct", payload)
+			}
+
+			var validationError ValidationError
+			validateStruct(value, "", &validationError)
+			if len(validationError.Violations) > 0 {
+				return payload, validationError.toHTTPError(http.StatusUnprocessableEntity)
+			}
+
+			return payload, nil
+		},
+	}
+}
+
+func validateStruct(value reflect.Value, path string, validationError *ValidationError) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, ok := bindingName(field, "json")
+		if !ok {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		fieldValue := value.Field(i)
+		for _, message := range validateField(fieldValue, field.Tag) {
+			validationError.Violations = append(validationError.Violations, FieldViolation{Field: name, Message: message})
+		}
+		validateNested(fieldValue, name, validationError)
+	}
+}
+
+func validateNested(value reflect.Value, path string, validationError *ValidationError) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		validateStruct(value, path, validationError)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			validateNested(value.Index(i), fmt.Sprintf("%s[%d]", path, i), validationError)
+		}
+	}
+}
+
+func validateField(value reflect.Value, tag reflect.StructTag) []string {
+	rules := strings.Split(tag.Get("validate"), ",")
+	pattern, hasPattern := tag.Lookup("pattern")
+
+	if value.IsZero() || (value.Kind() == reflect.Ptr && value.IsNil()) || (hasLength(value) && value.Len() == 0) {
+		for _, rule := range rules {
+			if strings.TrimSpace(rule) == "required" {
+				return []string{"is required"}
+			}
+		}
+	}
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	var messages []string
+	for _, rule := range rules {
+		name, arg := strings.TrimSpace(rule), ""
+		if i := strings.Index(name, "="); i >= 0 {
+			name, arg = name[:i], name[i+1:]
+		}
+
+		var message string
+		switch name {
+		case "", "required":
+		case "min", "max", "len":
+			message = validateBound(value, name, arg)
+		case "enum":
+			if !contains(strings.Split(arg, "|"), fmt.Sprint(value.Interface())) {
+				message = "must be one of " + strings.Join(strings.Split(arg, "|"), ", ")
+			}
+		case "email":
+			if value.Kind() != reflect.String {
+				message = fmt.Sprintf("rule %s can't be applied to %s", name, value.Type())
+			} else if address, err := mail.ParseAddress(value.String()); err != nil || address.Address != value.String() {
+				message = "must be a valid email address"
+			}
+		case "uuid":
+			if value.Kind() != reflect.String {
+				message = fmt.Sprintf("rule %s can't be applied to %s", name, value.Type())
+			} else if !uuidPattern.MatchString(value.String()) {
+				message = "must be a valid UUID"
+			}
+		default:
+			message = fmt.Sprintf("unknown validation rule %q", name)
+		}
+		if message != "" {
+			messages = append(messages, message)
+		}
+	}
+
+	if hasPattern {
+		re, err := compilePattern(pattern)
+		if value.Kind() != reflect.String {
+			messages = append(messages, fmt.Sprintf("pattern can't be applied to %s", value.Type()))
+		} else if err != nil {
+			messages = append(messages, fmt.Sprintf("invalid pattern %q", pattern))
+		} else if !re.MatchString(value.String()) {
+			messages = append(messages, fmt.Sprintf("must match the pattern %s", pattern))
+		}
+	}
+
+	return messages
+}
+
+func validateBound(value reflect.Value, rule string, arg string) string {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return fmt.Sprintf("invalid %s bound %q", rule, arg)
+	}
+
+	subject := "length"
+	var n float64
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, subject = float64(value.Int()), "value"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, subject = float64(value.Uint()), "value"
+	case reflect.Float32, reflect.Float64:
+		n, subject = value.Float(), "value"
+	case reflect.String:
+		n = float64(utf8.RuneCountInString(value.String()))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n = float64(value.Len())
+	default:
+		return fmt.Sprintf("rule %s can't be applied to %s", rule, value.Type())
+	}
+
+	switch {
+	case rule == "min" && n < bound:
+		return fmt.Sprintf("%s must be at least %s", subject, arg)
+	case rule == "max" && n > bound:
+		return fmt.Sprintf("%s must be at most %s", subject, arg)
+	case rule == "len" && n != bound:
+		return fmt.Sprintf("%s must be %s", subject, arg)
+	}
+
+	return ""
+}
+
+func hasLength(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	}
+
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+
+	return re, nil
+}
@@ -0,0 +1,156 @@
+package interceptors
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+)
+
+// WarmupPluginSource is the 'source' of the events sent by serverless-plugin-warmup
+const WarmupPluginSource = "serverless-plugin-warmup"
+
+type warmup struct {
+	sources  []string
+	rules    []string
+	matchers []func(map[string]interface{}) bool
+	hooks    []func(context.Context) error
+	response interface{}
+}
+
+// WarmupOption represents a configuration option for the SkipWarmup interceptor
+type WarmupOption func(*warmup)
+
+// WarmupSources sets the values of the 'source' field that identify warmup events. It defaults to
+// WarmupPluginSource
+func WarmupSources(sources ...string) WarmupOption {
+	return func(w *warmup) {
+		w.sources = sources
+	}
+}
+
+// WarmupRules identifies the scheduled events (events.CloudWatchEvent) triggered by the given EventBridge rules
+// (ARNs) as warmup events
+func WarmupRules(ruleARNs ...string) WarmupOption {
+	return func(w *warmup) {
+		w.rules = append(w.rules, ruleARNs...)
+	}
+}
+
+// WarmupMatcher adds a custom function that identifies warmup events. It receives the payload as a generic JSON object
+func WarmupMatcher(matcher func(map[string]interface{}) bool) WarmupOption {
+	return func(w *warmup) {
+		w.matchers = append(w.matchers, matcher)
+	}
+}
+
+// WarmupInit adds hooks that are executed when a warmup event is received (e.g. to open connections or load
+// configuration) so the first real invocation finds them ready. Hooks are executed in order and the first error is
+// returned
+func WarmupInit(hooks ...func(context.Context) error) WarmupOption {
+	return func(w *warmup) {
+		w.hooks = append(w.hooks, hooks...)
+	}
+}
+
+// WarmupResponse sets the response returned for warmup events. It defaults to nil
+func WarmupResponse(response interface{}) WarmupOption {
+	return func(w *warmup) {
+		w.response = response
+	}
+}
+
+// SkipWarmup returns immediately when the Lambda function receives a warmup (keep-alive) event, without executing the
+// rest of the chain. By default, the events sent by serverless-plugin-warmup are recognized. Other events are passed
+// to the rest of the chain unchanged.
+//
+// This interceptor is meant to be the first one provided, so warmup events don't reach other interceptors
+func SkipWarmup(options ...WarmupOption) gointercept.Interceptor {
+	warmup := warmup{sources: []string{WarmupPluginSource}}
+	for _, opt := range options {
+		opt(&warmup)
+	}
+
+	return gointercept.Interceptor{
+		Around: func(next gointercept.LambdaHandler) gointercept.LambdaHandler {
+			return func(ctx context.Context, payload interface{}) (interface{}, error) {
+				if !warmup.matches(payload) {
+					return next(ctx, payload)
+				}
+
+				for _, hook := range warmup.hooks {
+					if err := hook(ctx); err != nil {
+						return nil, err
+					}
+				}
+
+				return warmup.response, nil
+			}
+		},
+	}
+}
+
+// warmupEvent holds the fields that identify warmup events
+type warmupEvent struct {
+	Source     string   `json:"source"`
+	DetailType string   `json:"detail-type"`
+	Resources  []string `json:"resources"`
+}
+
+// matches reads only the fields that identify warmup events, so large payloads (e.g. SQS or Kafka batches) are not
+// converted to generic JSON objects on every invocation. That conversion is only done for custom matchers
+func (w *warmup) matches(payload interface{}) bool {
+	event, generic := payload.(map[string]interface{})
+
+	var fields warmupEvent
+	switch {
+	case generic:
+		fields.Source, _ = event["source"].(string)
+		fields.DetailType, _ = event["detail-type"].(string)
+		resources, _ := event["resources"].([]interface{})
+		for _, resource := range resources {
+			if resource, ok := resource.(string); ok {
+				fields.Resources = append(fields.Resources, resource)
+			}
+		}
+	default:
+		if cloudWatchEvent, ok := payload.(events.CloudWatchEvent); ok {
+			fields = warmupEvent{Source: cloudWatchEvent.Source, DetailType: cloudWatchEvent.DetailType,
+				Resources: cloudWatchEvent.Resources}
+		} else if err := internal.Decode(payload, &fields); err != nil {
+			fields = warmupEvent{}
+		}
+	}
+
+	for _, s := range w.sources {
+		if fields.Source == s {
+			return true
+		}
+	}
+
+	if fields.DetailType == "Scheduled Event" {
+		for _, resource := range fields.Resources {
+			for _, rule := range w.rules {
+				if resource == rule {
+					return true
+				}
+			}
+		}
+	}
+
+	if len(w.matchers) == 0 {
+		return false
+	}
+	if !generic {
+		if err := internal.Decode(payload, &event); err != nil || event == nil {
+			return false
+		}
+	}
+	for _, matcher := range w.matchers {
+		if matcher(event) {
+			return true
+		}
+	}
+
+	return false
+}
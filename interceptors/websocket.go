@@ -0,0 +1,124 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/internal"
+	"net/http"
+)
+
+// Route keys predefined by API Gateway WebSocket APIs
+const (
+	ConnectRoute    = "$connect"
+	DisconnectRoute = "$disconnect"
+	DefaultRoute    = "$default"
+)
+
+// WebSocketConnection describes the WebSocket connection that sent the current event
+type WebSocketConnection struct {
+	ID          string
+	RouteKey    string
+	DomainName  string
+	Stage       string
+	ConnectedAt int64
+}
+
+// CallbackURL returns the endpoint of the API Gateway management API used to post messages to the connection
+func (c WebSocketConnection) CallbackURL() string {
+	return fmt.Sprintf("https://%s/%s/@connections/%s", c.DomainName, c.Stage, c.ID)
+}
+
+type webSocketConnectionKey struct{}
+
+// GetWebSocketConnection returns the WebSocket connection added to the context by the WebSocketRouter, if any
+func GetWebSocketConnection(ctx context.Context) (WebSocketConnection, bool) {
+	connection, ok := ctx.Value(webSocketConnectionKey{}).(WebSocketConnection)
+	return connection, ok
+}
+
+// ConnectionID returns the ID of the WebSocket connection added to the context by the WebSocketRouter, if any
+func ConnectionID(ctx context.Context) string {
+	connection, _ := GetWebSocketConnection(ctx)
+	return connection.ID
+}
+
+// WebSocketRouter dispatches API Gateway WebSocket events to the handler mapped to their route key
+// (RequestContext.RouteKey). Events with an unmapped route key are sent to the DefaultRoute handler, if any.
+// Otherwise, they fail with a 404 HTTPError.
+//
+// The connection that sent the event is made available to the handlers through the context (see
+// GetWebSocketConnection and ConnectionID). Message bodies can be parsed with ParseBody as usual
+func WebSocketRouter(routes map[string]gointercept.LambdaHandler) gointercept.LambdaHandler {
+	return func(ctx context.Context, payload interface{}) (interface{}, error) {
+		request, err := getWebSocketRequest(payload)
+		if err != nil {
+			return payload, err
+		}
+
+		routeKey := request.RequestContext.RouteKey
+		handler, ok := routes[routeKey]
+		if !ok {
+			handler, ok = routes[DefaultRoute]
+		}
+		if !ok {
+			return payload, &HTTPError{StatusCode: http.StatusNotFound, StatusText: fmt.Sprintf("route %s not found", routeKey)}
+		}
+
+		connection := WebSocketConnection{
+			ID:          request.RequestContext.ConnectionID,
+			RouteKey:    routeKey,
+			DomainName:  request.RequestContext.DomainName,
+			Stage:       request.RequestContext.Stage,
+			ConnectedAt: request.RequestContext.ConnectedAt,
+		}
+
+		return handler(context.WithValue(ctx, webSocketConnectionKey{}, connection), request)
+	}
+}
+
+// CreateWebSocketResponse wraps the output of the Lambda function with the response expected by WebSocket routes.
+// Successful executions are answered with a 200 status code, which accepts the connection on the $connect route.
+// Errors are answered with the code of the HTTPError raised, if any, or with a 500 status code otherwise
+func CreateWebSocketResponse() gointercept.Interceptor {
+	return gointercept.Interceptor{
+		After: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			if payload == nil {
+				return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+			}
+
+			response, err := internal.ConvertToAPIGatewayResponse(payload)
+			if err != nil {
+				return payload, err
+			}
+			if response.StatusCode == 0 {
+				response.StatusCode = http.StatusOK
+			}
+
+			return response, nil
+		},
+		OnError: func(ctx context.Context, payload interface{}, err error) (interface{}, error) {
+			response := events.APIGatewayProxyResponse{StatusCode: http.StatusInternalServerError, Body: err.Error()}
+			if httpError, ok := err.(*HTTPError); ok {
+				response.StatusCode = httpError.StatusCode
+				response.Body = httpError.StatusText
+			}
+
+			return response, nil
+		},
+	}
+}
+
+func getWebSocketRequest(payload interface{}) (events.APIGatewayWebsocketProxyRequest, error) {
+	if request, ok := payload.(events.APIGatewayWebsocketProxyRequest); ok {
+		return request, nil
+	}
+
+	var request events.APIGatewayWebsocketProxyRequest
+	if err := internal.Decode(payload, &request); err != nil {
+		return request, fmt.Errorf("payload is not an API Gateway WebSocket request - %w", err)
+	}
+
+	return request, nil
+}
@@ -2,14 +2,17 @@ package internal
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"github.com/aws/aws-lambda-go/events"
 	"strings"
 )
 
 // ConvertToAPIGatewayResponse converts the value pointed to by the response parameter into an APIGatewayResponse instance. If the given parameter
 // is already an APIGatewayResponse, it is returned as is. Otherwise, a new instance is created and the given parameter
-// is attached as part of the body field
+// is attached as part of the body field. Binary ([]byte) parameters are attached base64-encoded, with IsBase64Encoded
+// set
 func ConvertToAPIGatewayResponse(response interface{}) (events.APIGatewayProxyResponse, error) {
 	var apiGatewayResponse events.APIGatewayProxyResponse
 
@@ -18,13 +21,15 @@ func ConvertToAPIGatewayResponse(response interface{}) (events.APIGatewayProxyRe
 	}
 
 	apiGatewayResponse = events.APIGatewayProxyResponse{}
-	body, ok := response.([]byte)
-	if !ok {
-		b, err := json.Marshal(response)
-		if err != nil {
-			return apiGatewayResponse, err
-		}
-		body = b
+	if body, ok := response.([]byte); ok {
+		apiGatewayResponse.Body = base64.StdEncoding.EncodeToString(body)
+		apiGatewayResponse.IsBase64Encoded = true
+		return apiGatewayResponse, nil
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		return apiGatewayResponse, err
 	}
 
 	var buf bytes.Buffer
@@ -34,14 +39,50 @@ func ConvertToAPIGatewayResponse(response interface{}) (events.APIGatewayProxyRe
 	return apiGatewayResponse, nil
 }
 
+// BodyGetter is implemented by payloads whose body is not stored in a 'body' field (e.g. AppSync resolver events,
+// whose body is their GraphQL arguments)
+type BodyGetter interface {
+	GetBody() (string, error)
+}
+
+// ConvertToFunctionURLResponse converts the value pointed to by the response parameter into a
+// LambdaFunctionURLResponse instance. If the given parameter is already a LambdaFunctionURLResponse, it is returned as
+// is. Otherwise, it is converted as done by ConvertToAPIGatewayResponse
+func ConvertToFunctionURLResponse(response interface{}) (events.LambdaFunctionURLResponse, error) {
+	if functionURLResponse, ok := response.(events.LambdaFunctionURLResponse); ok {
+		return functionURLResponse, nil
+	}
+
+	apiGatewayResponse, err := ConvertToAPIGatewayResponse(response)
+	if err != nil {
+		return events.LambdaFunctionURLResponse{}, err
+	}
+
+	return events.LambdaFunctionURLResponse{
+		StatusCode:      apiGatewayResponse.StatusCode,
+		Headers:         apiGatewayResponse.Headers,
+		Body:            apiGatewayResponse.Body,
+		IsBase64Encoded: apiGatewayResponse.IsBase64Encoded,
+	}, nil
+}
+
 type input struct {
-	Body string `json:"body"`
+	Body            string `json:"body"`
+	IsBase64Encoded bool   `json:"isBase64Encoded"`
 }
 
-// GetBody returns the contents of the Body field from the given parameter
+// GetBody returns the contents of the Body field from the given parameter. Base64-encoded bodies (IsBase64Encoded) are
+// decoded
 func GetBody(request interface{}) (string, error) {
-	if apiGatewayRequest, ok := request.(events.APIGatewayProxyRequest); ok {
-		return apiGatewayRequest.Body, nil
+	switch r := request.(type) {
+	case BodyGetter:
+		return r.GetBody()
+	case events.APIGatewayProxyRequest:
+		return decodeBody(r.Body, r.IsBase64Encoded)
+	case events.APIGatewayWebsocketProxyRequest:
+		return decodeBody(r.Body, r.IsBase64Encoded)
+	case events.LambdaFunctionURLRequest:
+		return decodeBody(r.Body, r.IsBase64Encoded)
 	}
 
 	bodyBytes, err := GetBytes(request)
@@ -55,7 +96,54 @@ func GetBody(request interface{}) (string, error) {
 	if err := decoder.Decode(&input); err != nil {
 		return "", err
 	}
-	return input.Body, nil
+	return decodeBody(input.Body, input.IsBase64Encoded)
+}
+
+type headers struct {
+	Headers map[string]string `json:"headers"`
+}
+
+// GetHeader returns the value of the given HTTP header (case-insensitive) from the given parameter. It returns an empty
+// string if the header, or the parameter's headers, can't be found
+func GetHeader(request interface{}, name string) string {
+	var h map[string]string
+	switch r := request.(type) {
+	case events.APIGatewayProxyRequest:
+		h = r.Headers
+	case events.APIGatewayWebsocketProxyRequest:
+		h = r.Headers
+	case events.APIGatewayV2HTTPRequest:
+		h = r.Headers
+	case events.LambdaFunctionURLRequest:
+		h = r.Headers
+	default:
+		var headers headers
+		if err := Decode(request, &headers); err != nil {
+			return ""
+		}
+		h = headers.Headers
+	}
+
+	for k, v := range h {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func decodeBody(body string, isBase64Encoded bool) (string, error) {
+	if !isBase64Encoded {
+		return body, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return "", fmt.Errorf("can't decode base64 body - %w", err)
+	}
+
+	return string(decoded), nil
 }
 
 // GetBytes returns the JSON encoding of key as a slice of bytes
@@ -68,3 +156,14 @@ func GetBytes(key interface{}) ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+// Decode stores the JSON encoding of the given payload in the value pointed to by target. It is used to turn generic
+// payloads (e.g. map[string]interface{}) into the typed events they represent
+func Decode(payload interface{}, target interface{}) error {
+	payloadBytes, err := GetBytes(payload)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payloadBytes, target)
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"testing"
+)
+
+func resolveContent(ctx context.Context, input Input) (*Output, error) {
+	if input.Value%2 != 0 {
+		return nil, &interceptors.AppSyncError{ErrorType: "OddValue", ErrorMessage: "Value is not even"}
+	}
+
+	return &Output{Status: interceptors.AppSyncClaims(ctx)["email"].(string), Content: input.Content}, nil
+}
+
+func appSyncEvent(arguments string) string {
+	return `{"arguments": ` + arguments + `, "identity": {"sub": "123", "claims": {"email": "jdoe@example.com"}}, "info": {"fieldName": "content"}}`
+}
+
+func TestHandleAppSyncResolver(t *testing.T) {
+	handler := gointercept.This(resolveContent).With(
+		interceptors.HandleAppSyncResolver(),
+		interceptors.ValidateBodyJSONSchema(schema),
+		interceptors.ParseBody(&Input{}, false),
+	)
+
+	var response Output
+	if err := executeHandler(handler, genericPayload(t, appSyncEvent(`{"content": "Random content", "value": 2}`)), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != "jdoe@example.com" || response.Content != "Random content" {
+		t.Errorf("Unexpected response %v", response)
+	}
+
+	cases := []struct {
+		scenario        string
+		arguments       string
+		expectedType    string
+		expectedMessage string
+	}{
+		{"Custom error type", `{"content": "Random content", "value": 1}`, "OddValue", "Value is not even"},
+		{"Validation error", `{"content": "Random content"}`, "UnprocessableEntity", `/: "value" value is required`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			_, err := handler(context.TODO(), genericPayload(t, appSyncEvent(c.arguments)))
+			invokeError, ok := err.(messages.InvokeResponse_Error)
+			if !ok {
+				t.Fatalf("Expected an invoke error, got %v", err)
+			}
+			if invokeError.Type != c.expectedType || invokeError.Message != c.expectedMessage {
+				t.Errorf("Unexpected error type '%s' and message '%s'", invokeError.Type, invokeError.Message)
+			}
+		})
+	}
+}
+
+func TestHandleAppSyncBatchResolver(t *testing.T) {
+	handler := gointercept.This(resolveContent).With(
+		interceptors.HandleAppSyncResolver(),
+		interceptors.ParseBody(&Input{}, false),
+	)
+
+	batch := "[" + appSyncEvent(`{"content": "first", "value": 2}`) + "," + appSyncEvent(`{"content": "second", "value": 4}`) + "," +
+		appSyncEvent(`{"content": "third", "unknown": 1}`) + "]"
+
+	var results []interceptors.AppSyncBatchResult
+	if err := executeHandler(handler, genericPayload(t, batch), &results); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Unexpected results %v", results)
+	}
+	for i, content := range []string{"first", "second"} {
+		data, _ := json.Marshal(results[i].Data)
+		if results[i].ErrorType != "" || string(data) != `{"Content":"`+content+`","Status":"jdoe@example.com"}` {
+			t.Errorf("Unexpected result %v", results[i])
+		}
+	}
+	if results[2].Data != nil || results[2].ErrorType != "UnprocessableEntity" {
+		t.Errorf("Unexpected result %v", results[2])
+	}
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"testing"
+)
+
+const methodArn = "arn:aws:execute-api:us-east-1:123456789012:abcdef1234/prod/GET/items/42"
+
+func tokenAuthorizer(request events.APIGatewayCustomAuthorizerRequest) (interceptors.AuthorizerDecision, error) {
+	return interceptors.AuthorizerDecision{
+		Allow:       request.AuthorizationToken == "secret",
+		PrincipalID: "user-1",
+		Context:     map[string]interface{}{"tenant": "acme"},
+	}, nil
+}
+
+func TestCreateAuthorizerResponse(t *testing.T) {
+	cases := []struct {
+		scenario         string
+		token            string
+		scope            interceptors.AuthorizerScope
+		expectedEffect   string
+		expectedResource string
+	}{
+		{"Allow method", "secret", interceptors.MethodScope, "Allow", methodArn},
+		{"Allow stage", "secret", interceptors.StageScope, "Allow", "arn:aws:execute-api:us-east-1:123456789012:abcdef1234/prod/*"},
+		{"Deny API", "wrong", interceptors.APIScope, "Deny", "arn:aws:execute-api:us-east-1:123456789012:abcdef1234/*"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			handler := gointercept.This(tokenAuthorizer).With(interceptors.CreateAuthorizerResponse(c.scope))
+			request := events.APIGatewayCustomAuthorizerRequest{Type: "TOKEN", AuthorizationToken: c.token, MethodArn: methodArn}
+
+			var response events.APIGatewayCustomAuthorizerResponse
+			if err := executeHandler(handler, request, &response); err != nil {
+				t.Fatal(err)
+			}
+
+			statement := response.PolicyDocument.Statement[0]
+			if response.PrincipalID != "user-1" || response.Context["tenant"] != "acme" {
+				t.Errorf("Unexpected principal or context in response %v", response)
+			}
+			if response.PolicyDocument.Version != "2012-10-17" || statement.Action[0] != "execute-api:Invoke" {
+				t.Errorf("Unexpected policy document %v", response.PolicyDocument)
+			}
+			if statement.Effect != c.expectedEffect || statement.Resource[0] != c.expectedResource {
+				t.Errorf("Unexpected statement %v", statement)
+			}
+		})
+	}
+}
+
+func TestCreateSimpleAuthorizerResponse(t *testing.T) {
+	handler := gointercept.This(func(request events.APIGatewayV2CustomAuthorizerV2Request) (*interceptors.AuthorizerDecision, error) {
+		return &interceptors.AuthorizerDecision{Allow: request.Headers["authorization"] == "secret", Context: map[string]interface{}{"tenant": "acme"}}, nil
+	}).With(interceptors.CreateSimpleAuthorizerResponse())
+
+	var response events.APIGatewayV2CustomAuthorizerSimpleResponse
+	request := events.APIGatewayV2CustomAuthorizerV2Request{Headers: map[string]string{"authorization": "secret"}}
+	if err := executeHandler(handler, request, &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if !response.IsAuthorized || response.Context["tenant"] != "acme" {
+		t.Errorf("Unexpected response %v", response)
+	}
+}
+
+func TestCreateAuthorizerResponseInvalidMethodArn(t *testing.T) {
+	for _, arn := range []string{"", "arn:aws:execute-api:us-east-1:123456789012:abcdef1234", "arn:aws:s3:::bucket/prod/GET/items", "abcdef1234/prod/GET/items"} {
+		called := false
+		handler := gointercept.This(func(request events.APIGatewayCustomAuthorizerRequest) (interceptors.AuthorizerDecision, error) {
+			called = true
+			return interceptors.AuthorizerDecision{Allow: true}, nil
+		}).With(interceptors.CreateAuthorizerResponse(interceptors.StageScope))
+
+		_, err := handler(context.TODO(), events.APIGatewayCustomAuthorizerRequest{Type: "TOKEN", AuthorizationToken: "secret", MethodArn: arn})
+		if err == nil || called {
+			t.Errorf("Expected an error for methodArn %q", arn)
+		}
+	}
+}
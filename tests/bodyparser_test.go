@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type Order struct {
+	Customer string   `json:"customer" form:"customer_name" xml:"customer"`
+	Quantity int      `json:"quantity" form:"qty" xml:"quantity"`
+	Express  bool     `json:"express" xml:"express,attr"`
+	Tags     []string `json:"tags" form:"tag" xml:"tag"`
+}
+
+func TestParseBodyContentTypes(t *testing.T) {
+	handler := gointercept.This(func(order Order) (Order, error) {
+		return order, nil
+	}).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+		interceptors.ParseBody(&Order{}, false,
+			interceptors.WithBodyDecoder("text/csv", func(body string, params map[string]string, target interface{}, allowUnknownFields bool) error {
+				fields := strings.Split(body, ",")
+				if len(fields) != 2 {
+					return errors.New("expected two columns")
+				}
+				target.(*Order).Customer = fields[0]
+				target.(*Order).Tags = strings.Split(fields[1], ";")
+				return nil
+			})),
+	)
+
+	multipartBody := "--XYZ\r\nContent-Disposition: form-data; name=\"customer_name\"\r\n\r\nJane\r\n" +
+		"--XYZ\r\nContent-Disposition: form-data; name=\"qty\"\r\n\r\n3\r\n" +
+		"--XYZ\r\nContent-Disposition: form-data; name=\"express\"\r\n\r\ntrue\r\n" +
+		"--XYZ\r\nContent-Disposition: form-data; name=\"tag\"\r\n\r\na\r\n" +
+		"--XYZ\r\nContent-Disposition: form-data; name=\"tag\"\r\n\r\nb\r\n" +
+		"--XYZ\r\nContent-Disposition: form-data; name=\"attachment\"; filename=\"a.txt\"\r\n\r\nignored\r\n--XYZ--\r\n"
+
+	cases := []struct {
+		scenario       string
+		contentType    string
+		body           string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{"JSON", "application/json; charset=utf-8", `{"customer": "Jane", "quantity": 3, "express": true, "tags": ["a", "b"]}`,
+			`{"customer":"Jane","quantity":3,"express":true,"tags":["a","b"]}`, http.StatusOK},
+		{"JSON suffix", "application/vnd.api+json", `{"customer": "Jane"}`, `{"customer":"Jane","quantity":0,"express":false,"tags":null}`, http.StatusOK},
+		{"No Content-Type", "", `{"customer": "Jane"}`, `{"customer":"Jane","quantity":0,"express":false,"tags":null}`, http.StatusOK},
+		{"Form", "application/x-www-form-urlencoded", "customer_name=Jane+Doe&qty=3&express=true&tag=a&tag=b",
+			`{"customer":"Jane Doe","quantity":3,"express":true,"tags":["a","b"]}`, http.StatusOK},
+		{"Form with unknown field", "application/x-www-form-urlencoded", "customer_name=Jane&color=red", `can't parse "customer_name=Jane&color=red" - unknown fields color`, http.StatusUnprocessableEntity},
+		{"Form with invalid value", "application/x-www-form-urlencoded", "qty=three", `can't parse "qty=three" - invalid value for qty - strconv.ParseInt: parsing "three": invalid syntax`, http.StatusUnprocessableEntity},
+		{"Multipart", "multipart/form-data; boundary=XYZ", multipartBody,
+			`{"customer":"Jane","quantity":3,"express":true,"tags":["a","b"]}`, http.StatusOK},
+		{"XML", "application/xml", `<order express="true"><customer>Jane</customer><quantity>3</quantity><tag>a</tag><tag>b</tag></order>`,
+			`{"customer":"Jane","quantity":3,"express":true,"tags":["a","b"]}`, http.StatusOK},
+		{"Custom decoder", "text/csv", "Jane,a;b", `{"customer":"Jane","quantity":0,"express":false,"tags":["a","b"]}`, http.StatusOK},
+		{"Unsupported media type", "application/yaml", "customer: Jane", `unsupported Content-Type "application/yaml"`, http.StatusUnsupportedMediaType},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			request := events.APIGatewayProxyRequest{Headers: map[string]string{"content-type": c.contentType}, Body: c.body}
+
+			var response events.APIGatewayProxyResponse
+			if err := executeHandler(handler, request, &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != c.expectedStatus || response.Body != c.expectedBody {
+				t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+			}
+		})
+	}
+
+	if _, err := handler(context.TODO(), map[string]interface{}{"headers": map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, "body": "qty=2"}); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestParseBodyMultipartLimits(t *testing.T) {
+	body := "--XYZ\r\nContent-Disposition: form-data; name=\"customer_name\"\r\n\r\nJane Doe\r\n--XYZ--\r\n"
+
+	cases := []struct {
+		scenario       string
+		options        []interceptors.ParseBodyOption
+		expectedStatus int
+	}{
+		{"Default limits", nil, http.StatusOK},
+		{"Part too large", []interceptors.ParseBodyOption{interceptors.WithMultipartLimits(4, 0)}, http.StatusRequestEntityTooLarge},
+		{"Body too large", []interceptors.ParseBodyOption{interceptors.WithMultipartLimits(0, 10)}, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			handler := gointercept.This(func(order Order) (Order, error) {
+				return order, nil
+			}).With(
+				interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+				interceptors.ParseBody(&Order{}, false, c.options...))
+
+			request := events.APIGatewayProxyRequest{Headers: map[string]string{"Content-Type": "multipart/form-data; boundary=XYZ"}, Body: body}
+			var response events.APIGatewayProxyResponse
+			if err := executeHandler(handler, request, &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != c.expectedStatus {
+				t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+			}
+		})
+	}
+}
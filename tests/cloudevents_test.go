@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"testing"
+)
+
+const structuredCloudEvent = `{"specversion": "1.0", "id": "1", "source": "/orders", "type": "order.created", "traceparent": "00-1", "data": {"content": "Random content", "value": 2}}`
+
+func cloudEventFunction(ctx context.Context, input Input) (*Output, error) {
+	event, _ := interceptors.GetCloudEvent(ctx)
+	output, err := simpleFunction(ctx, input)
+	if output != nil {
+		output.Status = event.Type
+	}
+	return output, err
+}
+
+func TestParseCloudEventFromAPIGateway(t *testing.T) {
+	handler := gointercept.This(cloudEventFunction).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusInternalServerError}),
+		interceptors.ParseCloudEvent())
+
+	cases := []struct {
+		scenario   string
+		request    events.APIGatewayProxyRequest
+		statusCode int
+		expected   string
+	}{
+		{"Structured mode", events.APIGatewayProxyRequest{HTTPMethod: "POST", Body: structuredCloudEvent}, http.StatusOK, "order.created"},
+		{"Binary mode", events.APIGatewayProxyRequest{HTTPMethod: "POST", Headers: map[string]string{
+			"Ce-Specversion": "1.0", "Ce-Id": "1", "Ce-Source": "/orders", "Ce-Type": "order.updated", "Content-Type": "application/json",
+		}, Body: `{"content": "Random content", "value": 2}`}, http.StatusOK, "order.updated"},
+		{"Base64-encoded structured mode", events.APIGatewayProxyRequest{HTTPMethod: "POST", IsBase64Encoded: true,
+			Body: base64.StdEncoding.EncodeToString([]byte(structuredCloudEvent))}, http.StatusOK, "order.created"},
+		{"Missing attributes", events.APIGatewayProxyRequest{HTTPMethod: "POST", Body: `{"specversion": "1.0", "id": "1", "data": {}}`}, http.StatusBadRequest, ""},
+		{"Unsupported version", events.APIGatewayProxyRequest{HTTPMethod: "POST", Headers: map[string]string{
+			"ce-specversion": "0.3", "ce-id": "1", "ce-source": "/orders", "ce-type": "order.updated",
+		}}, http.StatusBadRequest, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			var response events.APIGatewayProxyResponse
+			if err := executeHandler(handler, c.request, &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != c.statusCode {
+				t.Fatalf("Expected status code %d, got %d (%s)", c.statusCode, response.StatusCode, response.Body)
+			}
+			if c.expected == "" {
+				return
+			}
+			var output Output
+			if err := json.Unmarshal([]byte(response.Body), &output); err != nil {
+				t.Fatal(err)
+			}
+			if output.Status != c.expected || output.Content != "Random content" {
+				t.Errorf("Unexpected output %v", output)
+			}
+		})
+	}
+}
+
+func TestParseCloudEventBinaryData(t *testing.T) {
+	handler := gointercept.This(func(ctx context.Context, data []byte) (string, error) {
+		return hex.EncodeToString(data), nil
+	}).With(interceptors.ParseCloudEvent())
+
+	request := events.APIGatewayProxyRequest{HTTPMethod: "POST", IsBase64Encoded: true, Headers: map[string]string{
+		"ce-specversion": "1.0", "ce-id": "1", "ce-source": "/files", "ce-type": "file.uploaded", "content-type": "application/octet-stream",
+	}, Body: base64.StdEncoding.EncodeToString([]byte{0xff, 0x00, 0xfe, 0x41})}
+
+	response, err := handler(context.TODO(), request)
+	if err != nil || response != "ff00fe41" {
+		t.Errorf("Unexpected response %v %v", response, err)
+	}
+
+	textHandler := gointercept.This(func(ctx context.Context, data string) (string, error) {
+		return data, nil
+	}).With(interceptors.ParseCloudEvent())
+	request.IsBase64Encoded = false
+	request.Headers["content-type"] = "text/plain; charset=utf-8"
+	request.Body = "héllo"
+	if response, err := textHandler(context.TODO(), request); err != nil || response != "héllo" {
+		t.Errorf("Unexpected response %v %v", response, err)
+	}
+}
+
+func TestParseCloudEventFromFunctionURL(t *testing.T) {
+	handler := gointercept.This(cloudEventFunction).With(
+		interceptors.CreateFunctionURLResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusInternalServerError}),
+		interceptors.ParseCloudEvent())
+
+	request := events.LambdaFunctionURLRequest{
+		Version:         "2.0",
+		RequestContext:  events.LambdaFunctionURLRequestContext{HTTP: events.LambdaFunctionURLRequestContextHTTPDescription{Method: "POST"}},
+		Headers:         map[string]string{"ce-specversion": "1.0", "ce-id": "1", "ce-source": "/orders", "ce-type": "order.updated"},
+		Body:            base64.StdEncoding.EncodeToString([]byte(`{"content": "Random content", "value": 2}`)),
+		IsBase64Encoded: true,
+	}
+
+	var response events.LambdaFunctionURLResponse
+	if err := executeHandler(handler, request, &response); err != nil {
+		t.Fatal(err)
+	}
+	var output Output
+	if err := json.Unmarshal([]byte(response.Body), &output); err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusOK || output.Status != "order.updated" || output.Content != "Random content" {
+		t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+	}
+}
+
+func TestParseCloudEventFromSQS(t *testing.T) {
+	handler := gointercept.This(cloudEventFunction).With(interceptors.ParseCloudEvent())
+
+	request := events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "a", EventSource: "aws:sqs", Body: structuredCloudEvent},
+		{MessageId: "b", EventSource: "aws:sqs", Body: `{"specversion": "1.0", "id": "2", "source": "/orders", "type": "order.created", "data": {"value": 1}}`},
+		{MessageId: "c", EventSource: "aws:sqs", Body: `not a CloudEvent`},
+	}}
+
+	responses, err := handler(context.TODO(), request)
+
+	var batchError *interceptors.BatchError
+	if !errors.As(err, &batchError) {
+		t.Fatalf("Expected a BatchError, got %v", err)
+	}
+	if len(batchError.Errors) != 2 || batchError.Errors[0].ID != "b" || !errors.Is(batchError.Errors[1], interceptors.ErrInvalidCloudEvent) {
+		t.Errorf("Unexpected errors %v", batchError)
+	}
+	if r, ok := responses.([]interface{}); !ok || len(r) != 1 {
+		t.Errorf("Unexpected responses %v", responses)
+	}
+}
+
+func TestCreateCloudEventResponse(t *testing.T) {
+	handler := gointercept.This(simpleFunction).With(
+		interceptors.CreateCloudEventResponse("/processor", "order.processed"))
+
+	response, err := handler(context.TODO(), Input{Content: "Random content", Value: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var event interceptors.CloudEvent
+	if err := decode(response, &event); err != nil {
+		t.Fatal(err)
+	}
+	var output Output
+	if err := json.Unmarshal(event.Data, &output); err != nil {
+		t.Fatal(err)
+	}
+
+	if event.SpecVersion != "1.0" || event.ID == "" || event.Time == nil || event.Source != "/processor" ||
+		event.Type != "order.processed" || output.Content != "Random content" {
+		t.Errorf("Unexpected CloudEvent %v", event)
+	}
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"testing"
+)
+
+func cloudFrontResponseEvent() interceptors.CloudFrontEvent {
+	return interceptors.CloudFrontEvent{Records: []interceptors.CloudFrontRecord{{
+		CF: interceptors.CloudFrontRecordBody{
+			Config:  interceptors.CloudFrontConfig{EventType: "viewer-response"},
+			Request: &interceptors.CloudFrontRequest{URI: "/index.html"},
+			Response: &interceptors.CloudFrontResponse{
+				Status:  "200",
+				Headers: interceptors.CloudFrontHeaders{"content-type": {{Key: "Content-Type", Value: "text/html"}}},
+			},
+		},
+	}}}
+}
+
+func TestCloudFrontSecurityHeaders(t *testing.T) {
+	handler := gointercept.This(func(event interceptors.CloudFrontEvent) (interceptors.CloudFrontEvent, error) {
+		return event, nil
+	}).With(
+		interceptors.AddHeaders(map[string]string{"Cache-Control": "max-age=60"}),
+		interceptors.AddSecurityHeaders(),
+	)
+
+	var response interceptors.CloudFrontResponse
+	if err := executeHandler(handler, cloudFrontResponseEvent(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHeaders := map[string]string{
+		"Content-Type":              "text/html",
+		"Cache-Control":             "max-age=60",
+		"X-Frame-Options":           "DENY",
+		"Strict-Transport-Security": "15552000; includeSubDomains; preLoad",
+		"X-Content-Type-Options":    "nosniff",
+	}
+	for key, value := range expectedHeaders {
+		if response.Headers.Get(key) != value {
+			t.Errorf("Expected header '%s: %s' in response not found", key, value)
+		}
+	}
+	if header := response.Headers["x-frame-options"]; len(header) != 1 || header[0].Key != "X-Frame-Options" {
+		t.Errorf("Unexpected CloudFront header format %v", header)
+	}
+}
+
+func TestCloudFrontNormalizeRequestHeaders(t *testing.T) {
+	var headers interceptors.CloudFrontHeaders
+	handler := gointercept.This(func(event interceptors.CloudFrontEvent) (interceptors.CloudFrontRequest, error) {
+		headers = event.Records[0].CF.Request.Headers
+		return *event.Records[0].CF.Request, nil
+	}).With(interceptors.NormalizeHTTPRequestHeaders(true))
+
+	request := interceptors.CloudFrontEvent{Records: []interceptors.CloudFrontRecord{{
+		CF: interceptors.CloudFrontRecordBody{Request: &interceptors.CloudFrontRequest{
+			Headers: interceptors.CloudFrontHeaders{
+				"content-type": {{Key: "content-type", Value: "application/json"}},
+				"x-uidh":       {{Value: "1"}},
+			},
+		}},
+	}}}
+
+	var response interceptors.CloudFrontRequest
+	if err := executeHandler(handler, request, &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if headers["content-type"][0].Key != "Content-Type" || headers["x-uidh"][0].Key != "X-UIDH" {
+		t.Errorf("Unexpected normalized headers %v", headers)
+	}
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"strings"
+	"testing"
+)
+
+func TestCreateCognitoTriggerResponse(t *testing.T) {
+	handler := gointercept.This(func(event events.CognitoEventUserPoolsPreSignup) (events.CognitoEventUserPoolsPreSignupResponse, error) {
+		return events.CognitoEventUserPoolsPreSignupResponse{
+			AutoConfirmUser: strings.HasSuffix(event.Request.UserAttributes["email"], "@example.com"),
+		}, nil
+	}).With(interceptors.CreateCognitoTriggerResponse())
+
+	request := events.CognitoEventUserPoolsPreSignup{
+		CognitoEventUserPoolsHeader: events.CognitoEventUserPoolsHeader{
+			Version:       "1",
+			TriggerSource: "PreSignUp_SignUp",
+			UserPoolID:    "us-east-1_abc",
+			UserName:      "jdoe",
+		},
+		Request: events.CognitoEventUserPoolsPreSignupRequest{UserAttributes: map[string]string{"email": "jdoe@example.com"}},
+	}
+
+	var response events.CognitoEventUserPoolsPreSignup
+	if err := executeHandler(handler, request, &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if !response.Response.AutoConfirmUser || response.Response.AutoVerifyEmail {
+		t.Errorf("Unexpected response section %v", response.Response)
+	}
+	if response.UserName != "jdoe" || response.TriggerSource != "PreSignUp_SignUp" || response.Request.UserAttributes["email"] != "jdoe@example.com" {
+		t.Errorf("Event was not echoed back %v", response)
+	}
+}
+
+func TestCreateCognitoTriggerResponseValidation(t *testing.T) {
+	request := events.CognitoEventUserPoolsCustomMessage{
+		CognitoEventUserPoolsHeader: events.CognitoEventUserPoolsHeader{TriggerSource: "CustomMessage_SignUp"},
+		Request:                     events.CognitoEventUserPoolsCustomMessageRequest{CodeParameter: "{####}"},
+	}
+
+	cases := []struct {
+		scenario string
+		handler  interface{}
+		valid    bool
+	}{
+		{
+			scenario: "Valid custom message",
+			handler: func() (events.CognitoEventUserPoolsCustomMessageResponse, error) {
+				return events.CognitoEventUserPoolsCustomMessageResponse{EmailSubject: "Welcome", EmailMessage: "Your code is {####}"}, nil
+			},
+			valid: true,
+		},
+		{
+			scenario: "Custom message without code",
+			handler: func() (events.CognitoEventUserPoolsCustomMessageResponse, error) {
+				return events.CognitoEventUserPoolsCustomMessageResponse{EmailMessage: "Welcome!"}, nil
+			},
+		},
+		{
+			scenario: "Unknown response field",
+			handler: func() (map[string]interface{}, error) {
+				return map[string]interface{}{"autoConfirmUser": true}, nil
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			handler := gointercept.This(c.handler).With(interceptors.CreateCognitoTriggerResponse())
+			_, err := handler(context.TODO(), request)
+			if c.valid && err != nil {
+				t.Errorf("Unexpected error %v", err)
+			}
+			if !c.valid && err == nil {
+				t.Errorf("Expected a validation error")
+			}
+		})
+	}
+
+	handler := gointercept.This(func() (map[string]interface{}, error) {
+		return map[string]interface{}{"smsMessage": 1, "emailMessage": true}, nil
+	}).With(interceptors.CreateCognitoTriggerResponse())
+	_, err := handler(context.TODO(), request)
+	var validationError *interceptors.ValidationError
+	if !errors.As(err, &validationError) || len(validationError.Violations) != 2 {
+		t.Errorf("Expected all violations to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleCustomResource(t *testing.T) {
+	cases := []struct {
+		scenario               string
+		handler                interface{}
+		timeout                time.Duration
+		physicalResourceID     string
+		expectedStatus         cfn.StatusType
+		expectedReason         string
+		expectedPhysicalID     string
+		expectedData           map[string]interface{}
+		expectedFunctionFailed bool
+	}{
+		{
+			scenario: "Success",
+			handler: func(event cfn.Event) (interceptors.CustomResourceResult, error) {
+				return interceptors.CustomResourceResult{
+					PhysicalResourceID: "resource-1",
+					Data:               map[string]interface{}{"Name": event.ResourceProperties["Name"], "Count": 2},
+				}, nil
+			},
+			expectedStatus:     cfn.StatusSuccess,
+			expectedPhysicalID: "resource-1",
+			expectedData:       map[string]interface{}{"Name": "foo", "Count": "2"},
+		},
+		{
+			scenario: "Error keeps the physical resource ID",
+			handler: func(event cfn.Event) (interceptors.CustomResourceResult, error) {
+				return interceptors.CustomResourceResult{}, errors.New("can't update resource")
+			},
+			physicalResourceID: "resource-1",
+			expectedStatus:     cfn.StatusFailed,
+			expectedReason:     "can't update resource",
+			expectedPhysicalID: "resource-1",
+		},
+		{
+			scenario: "Panic",
+			handler: func(event cfn.Event) (interceptors.CustomResourceResult, error) {
+				panic("boom")
+			},
+			expectedStatus:     cfn.StatusFailed,
+			expectedReason:     "handler panicked: boom",
+			expectedPhysicalID: "Resource-request-1",
+		},
+		{
+			scenario: "Long reason",
+			handler: func(event cfn.Event) (interceptors.CustomResourceResult, error) {
+				return interceptors.CustomResourceResult{}, errors.New(strings.Repeat("a", 1023) + "é")
+			},
+			expectedStatus:     cfn.StatusFailed,
+			expectedReason:     strings.Repeat("a", 1023),
+			expectedPhysicalID: "Resource-request-1",
+		},
+		{
+			scenario: "Data too large",
+			handler: func(event cfn.Event) (interceptors.CustomResourceResult, error) {
+				return interceptors.CustomResourceResult{
+					PhysicalResourceID: "resource-1",
+					Data:               map[string]interface{}{"Blob": strings.Repeat("a", 4096)},
+				}, nil
+			},
+			expectedStatus:     cfn.StatusFailed,
+			expectedReason:     "custom resource data is 4107 bytes, more than the 4096 bytes allowed",
+			expectedPhysicalID: "Resource-request-1",
+		},
+		{
+			scenario: "Deadline",
+			handler: func(ctx context.Context, event cfn.Event) (interceptors.CustomResourceResult, error) {
+				<-ctx.Done()
+				return interceptors.CustomResourceResult{}, ctx.Err()
+			},
+			timeout:            50 * time.Millisecond,
+			expectedStatus:     cfn.StatusFailed,
+			expectedReason:     "handler did not finish before the Lambda function's deadline",
+			expectedPhysicalID: "Resource-request-1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			var sent cfn.Response
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut {
+					t.Errorf("Unexpected method %s", r.Method)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+					t.Error(err)
+				}
+			}))
+			defer server.Close()
+
+			handler := gointercept.This(c.handler).With(
+				interceptors.HandleCustomResource(
+					interceptors.CustomResourceHTTPClient(server.Client()),
+					interceptors.CustomResourceDeadlineMargin(10*time.Millisecond)),
+			)
+
+			ctx := context.Background()
+			if c.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, c.timeout)
+				defer cancel()
+			}
+
+			event := cfn.Event{
+				RequestType:        cfn.RequestCreate,
+				RequestID:          "request-1",
+				ResponseURL:        server.URL,
+				LogicalResourceID:  "Resource",
+				PhysicalResourceID: c.physicalResourceID,
+				StackID:            "stack-1",
+				ResourceProperties: map[string]interface{}{"Name": "foo"},
+			}
+			if _, err := handler(ctx, event); err != nil {
+				t.Fatal(err)
+			}
+
+			if sent.Status != c.expectedStatus || sent.Reason != c.expectedReason {
+				t.Errorf("Unexpected status '%s' and reason '%s' in response", sent.Status, sent.Reason)
+			}
+			if sent.PhysicalResourceID != c.expectedPhysicalID || sent.RequestID != "request-1" || sent.StackID != "stack-1" {
+				t.Errorf("Unexpected identifiers in response %v", sent)
+			}
+			for key, value := range c.expectedData {
+				if sent.Data[key] != value {
+					t.Errorf("Expected data '%s: %v' in response not found", key, value)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleCustomResourceCancelsHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	stopped := make(chan struct{})
+	handler := gointercept.This(func(ctx context.Context, event cfn.Event) (interceptors.CustomResourceResult, error) {
+		<-ctx.Done()
+		close(stopped)
+		return interceptors.CustomResourceResult{}, ctx.Err()
+	}).With(
+		interceptors.HandleCustomResource(
+			interceptors.CustomResourceHTTPClient(server.Client()),
+			interceptors.CustomResourceDeadlineMargin(time.Second)),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second+50*time.Millisecond)
+	defer cancel()
+	if _, err := handler(ctx, cfn.Event{RequestID: "request-1", ResponseURL: server.URL}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(500 * time.Millisecond):
+		t.Error("The handler's context was not cancelled when the FAILED response was sent")
+	}
+}
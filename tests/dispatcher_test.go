@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func genericPayload(t *testing.T, raw string) interface{} {
+	var payload interface{}
+	if err := json.Unmarshal([]byte(raw), &payload); err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+func TestDispatcher(t *testing.T) {
+	handler := gointercept.This(interceptors.Dispatcher(map[interceptors.EventSource]gointercept.LambdaHandler{
+		interceptors.APIGatewaySource: gointercept.This(simpleFunction).With(
+			interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+			interceptors.ParseBody(&Input{}, false)),
+		interceptors.SQSSource: gointercept.This(func(event events.SQSEvent) (string, error) {
+			return "sqs:" + event.Records[0].Body, nil
+		}).With(),
+		interceptors.ScheduledEventSource: gointercept.This(func(event events.CloudWatchEvent) (string, error) {
+			return "schedule:" + event.ID, nil
+		}).With(),
+	})).With()
+
+	cases := []struct {
+		scenario string
+		request  string
+		expected string
+	}{
+		{"API Gateway", `{"httpMethod": "POST", "path": "/", "body": "{\"content\": \"api\", \"value\": 2}"}`, "200"},
+		{"SQS", `{"Records": [{"eventSource": "aws:sqs", "body": "message"}]}`, "sqs:message"},
+		{"Schedule", `{"id": "42", "source": "aws.events", "detail-type": "Scheduled Event", "detail": {}}`, "schedule:42"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			response, err := handler(context.TODO(), genericPayload(t, c.request))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if apiGatewayResponse, ok := response.(events.APIGatewayProxyResponse); ok {
+				response = strconv.Itoa(apiGatewayResponse.StatusCode)
+			}
+			if response != c.expected {
+				t.Errorf("Unexpected response %v", response)
+			}
+		})
+	}
+
+	if _, err := handler(context.TODO(), genericPayload(t, `{"Records": [{"eventSource": "aws:sns"}]}`)); !errors.Is(err, interceptors.ErrUnrecognizedEvent) {
+		t.Errorf("Expected unrecognized event error for SNS event, got %v", err)
+	}
+	if _, err := handler(context.TODO(), genericPayload(t, `{"foo": "bar"}`)); !errors.Is(err, interceptors.ErrUnrecognizedEvent) {
+		t.Errorf("Expected unrecognized event error, got %v", err)
+	}
+}
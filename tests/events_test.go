@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"strings"
+	"testing"
+)
+
+func s3Record(bucket, key string) events.S3EventRecord {
+	return events.S3EventRecord{
+		EventName: "ObjectCreated:Put",
+		S3: events.S3Entity{
+			Bucket: events.S3Bucket{Name: bucket},
+			Object: events.S3Object{Key: key, Size: 10, ETag: "abc"},
+		},
+	}
+}
+
+func TestParseS3Event(t *testing.T) {
+	request := events.S3Event{Records: []events.S3EventRecord{
+		s3Record("bucket", "my+folder/file%281%29.txt"),
+		s3Record("bucket", "other.txt"),
+	}}
+
+	handler := gointercept.This(func(objects []interceptors.S3Object) ([]string, error) {
+		keys := make([]string, len(objects))
+		for i, object := range objects {
+			keys[i] = object.Bucket + "/" + object.Key
+		}
+		return keys, nil
+	}).With(interceptors.ParseS3Event(false))
+
+	var keys []string
+	if err := executeHandler(handler, request, &keys); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 2 || keys[0] != "bucket/my folder/file(1).txt" || keys[1] != "bucket/other.txt" {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+}
+
+func TestParseS3EventForEachObject(t *testing.T) {
+	request := events.S3Event{Records: []events.S3EventRecord{
+		s3Record("bucket", "first.txt"),
+		s3Record("bucket", "bad.txt"),
+		s3Record("bucket", "bad%zz.txt"),
+		s3Record("bucket", "last.txt"),
+	}}
+
+	var processed []string
+	handler := gointercept.This(func(object interceptors.S3Object) (string, error) {
+		if object.Key == "bad.txt" {
+			return "", errors.New("can't process object")
+		}
+		processed = append(processed, object.Key)
+		return object.Key, nil
+	}).With(interceptors.ParseS3Event(true))
+
+	_, err := handler(context.TODO(), request)
+
+	var batchError *interceptors.BatchError
+	if !errors.As(err, &batchError) {
+		t.Fatalf("Expected a BatchError, got %v", err)
+	}
+	if len(batchError.Errors) != 2 || batchError.Errors[0].ID != "bucket/bad.txt" || batchError.Errors[1].ID != "bucket/bad%zz.txt" {
+		t.Errorf("Unexpected errors %v", batchError)
+	}
+	if len(processed) != 2 || processed[0] != "first.txt" || processed[1] != "last.txt" {
+		t.Errorf("Unexpected processed objects %v", processed)
+	}
+}
+
+func kafkaRecord(partition, offset int64, value string) events.KafkaRecord {
+	return events.KafkaRecord{
+		Topic:     "orders",
+		Partition: partition,
+		Offset:    offset,
+		Value:     base64.StdEncoding.EncodeToString([]byte(value)),
+	}
+}
+
+func kafkaEvent() events.KafkaEvent {
+	return events.KafkaEvent{
+		EventSource: "aws:kafka",
+		Records: map[string][]events.KafkaRecord{
+			"orders-0": {kafkaRecord(0, 10, `{"content": "a", "value": 2}`), kafkaRecord(0, 11, `{"content": "b", "value": 1}`), kafkaRecord(0, 12, `{"content": "c", "value": 2}`)},
+			"orders-1": {kafkaRecord(1, 5, `{"content": "d", "value": 2}`), kafkaRecord(1, 6, `not json`)},
+			"orders-2": {kafkaRecord(2, 7, `{"content": "e", "value": 2}`)},
+		},
+	}
+}
+
+func TestParseKafkaEventPerRecord(t *testing.T) {
+	var processed []string
+	handler := gointercept.This(func(message struct {
+		Offset int64 `json:"offset"`
+		Value  Input `json:"value"`
+	}) error {
+		if message.Value.Value%2 != 0 {
+			return errors.New("Value is not even")
+		}
+		processed = append(processed, message.Value.Content)
+		return nil
+	}).With(interceptors.ParseKafkaEvent(false, interceptors.KafkaJSONValue))
+
+	_, err := handler(context.TODO(), kafkaEvent())
+
+	var batchError *interceptors.BatchError
+	if !errors.As(err, &batchError) {
+		t.Fatalf("Expected a BatchError, got %v", err)
+	}
+
+	expectedIDs := []string{"orders-0@11", "orders-0@12", "orders-1@6"}
+	if len(batchError.Errors) != len(expectedIDs) {
+		t.Fatalf("Unexpected errors %v", batchError)
+	}
+	for i, id := range expectedIDs {
+		if batchError.Errors[i].ID != id {
+			t.Errorf("Unexpected failed record %s", batchError.Errors[i].ID)
+		}
+	}
+	if !errors.Is(batchError.Errors[1], interceptors.ErrKafkaRecordSkipped) {
+		t.Errorf("Expected record to be skipped %v", batchError.Errors[1])
+	}
+	if strings.Join(processed, ",") != "a,d,e" {
+		t.Errorf("Unexpected processed records %v", processed)
+	}
+}
+
+func TestParseKafkaEventPerPartition(t *testing.T) {
+	event := events.KafkaEvent{Records: map[string][]events.KafkaRecord{
+		"orders-0": {kafkaRecord(0, 11, "second"), kafkaRecord(0, 10, "first")},
+	}}
+
+	handler := gointercept.This(func(messages []struct {
+		Value []byte `json:"value"`
+	}) (string, error) {
+		var values []string
+		for _, message := range messages {
+			values = append(values, string(message.Value))
+		}
+		return strings.Join(values, ","), nil
+	}).With(interceptors.ParseKafkaEvent(true, interceptors.KafkaRawValue))
+
+	var responses []string
+	if err := executeHandler(handler, event, &responses); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 1 || responses[0] != "first,second" {
+		t.Errorf("Unexpected responses %v", responses)
+	}
+	if event.Records["orders-0"][0].Offset != 11 {
+		t.Errorf("The records of the event were reordered")
+	}
+}
+
+func TestParseKafkaEventPerPartitionDecodeFailure(t *testing.T) {
+	event := events.KafkaEvent{Records: map[string][]events.KafkaRecord{
+		"orders-0": {kafkaRecord(0, 1, `{"content": "a"}`), kafkaRecord(0, 2, `{"content": "b"}`), kafkaRecord(0, 3, `not json`),
+			kafkaRecord(0, 4, `{"content": "d"}`)},
+	}}
+
+	var calls [][]int64
+	handler := gointercept.This(func(messages []struct {
+		Offset int64 `json:"offset"`
+	}) error {
+		var offsets []int64
+		for _, message := range messages {
+			offsets = append(offsets, message.Offset)
+		}
+		calls = append(calls, offsets)
+		return nil
+	}).With(interceptors.ParseKafkaEvent(true, interceptors.KafkaJSONValue))
+
+	_, err := handler(context.TODO(), event)
+
+	var batchError *interceptors.BatchError
+	if !errors.As(err, &batchError) {
+		t.Fatalf("Expected a BatchError, got %v", err)
+	}
+	if len(batchError.Errors) != 2 || batchError.Errors[0].ID != "orders-0@3" || batchError.Errors[1].ID != "orders-0@4" ||
+		!errors.Is(batchError.Errors[1], interceptors.ErrKafkaRecordSkipped) {
+		t.Errorf("Unexpected errors %v", batchError)
+	}
+	if len(calls) != 1 || len(calls[0]) != 2 || calls[0][0] != 1 || calls[0][1] != 2 {
+		t.Errorf("Unexpected calls %v", calls)
+	}
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"encoding/base64"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"testing"
+)
+
+func TestFunctionURLRequestResponse(t *testing.T) {
+	handler := gointercept.This(simpleFunction).With(
+		interceptors.AddHeaders(map[string]string{"Content-Type": "application/json", "Set-Cookie": "session=abc; Secure"}),
+		interceptors.AddSecurityHeaders(),
+		interceptors.CreateFunctionURLResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+		interceptors.NormalizeHTTPRequestHeaders(false),
+		interceptors.ParseBody(&Input{}, false),
+	)
+
+	cases := []struct {
+		scenario       string
+		request        events.LambdaFunctionURLRequest
+		expectedBody   string
+		expectedStatus int
+	}{
+		{
+			scenario:       "Plain body",
+			request:        events.LambdaFunctionURLRequest{Body: `{"content": "Random content", "value": 2}`},
+			expectedBody:   `{"Status":"Function ran successfully!","Content":"Random content"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			scenario: "Base64-encoded body",
+			request: events.LambdaFunctionURLRequest{
+				Body:            base64.StdEncoding.EncodeToString([]byte(`{"content": "Random content", "value": 2}`)),
+				IsBase64Encoded: true,
+				Headers:         map[string]string{"Content-Type": "application/json"},
+			},
+			expectedBody:   `{"Status":"Function ran successfully!","Content":"Random content"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			scenario:       "Error",
+			request:        events.LambdaFunctionURLRequest{Body: `{"content": "Random content", "value": 1}`},
+			expectedBody:   `Value is not even`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			var response events.LambdaFunctionURLResponse
+			if err := executeHandler(handler, c.request, &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if response.Body != c.expectedBody {
+				t.Errorf("Unexpected content '%s' in response's body", response.Body)
+			}
+			if response.StatusCode != c.expectedStatus {
+				t.Errorf("Unexpected status '%d' in response", response.StatusCode)
+			}
+			if response.Headers["Content-Type"] != "application/json" || response.Headers["X-Frame-Options"] != "DENY" {
+				t.Errorf("Expected headers in response not found %v", response.Headers)
+			}
+			if len(response.Cookies) != 1 || response.Cookies[0] != "session=abc; Secure" {
+				t.Errorf("Unexpected cookies %v", response.Cookies)
+			}
+			if c.request.Headers != nil && c.request.Headers["content-type"] != "application/json" {
+				t.Errorf("Expected normalized request headers %v", c.request.Headers)
+			}
+		})
+	}
+}
+
+func TestFunctionURLResponseHTTPErrorHeaders(t *testing.T) {
+	httpError := &interceptors.HTTPError{StatusCode: http.StatusTooManyRequests, StatusText: "slow down", Headers: map[string]string{"Retry-After": "1"}}
+	handler := gointercept.This(func(input Input) (*Output, error) {
+		return nil, httpError
+	}).With(
+		interceptors.AddHeaders(map[string]string{"Content-Type": "application/json"}),
+		interceptors.CreateFunctionURLResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+	)
+
+	var response events.LambdaFunctionURLResponse
+	if err := executeHandler(handler, events.LambdaFunctionURLRequest{Body: `{}`}, &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusTooManyRequests || response.Headers["Retry-After"] != "1" || response.Headers["Content-Type"] != "application/json" {
+		t.Errorf("Unexpected response %v", response)
+	}
+	if len(httpError.Headers) != 1 {
+		t.Errorf("The headers of the HTTPError were modified %v", httpError.Headers)
+	}
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"github.com/aws/aws-lambda-go/events"
@@ -73,6 +74,34 @@ func TestParseCustomInput(t *testing.T) {
 	}
 }
 
+func TestBase64EncodedBodies(t *testing.T) {
+	body := base64.StdEncoding.EncodeToString([]byte("{\"content\": \"Random content\", \"value\": 2 }"))
+	handler := gointercept.This(func(input Input) ([]byte, error) {
+		return []byte(input.Content), nil
+	}).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+		interceptors.ValidateBodyJSONSchema(schema),
+		interceptors.ParseBody(&Input{}, false),
+	)
+
+	requests := []interface{}{
+		events.APIGatewayProxyRequest{Body: body, IsBase64Encoded: true},
+		map[string]interface{}{"body": body, "isBase64Encoded": true},
+	}
+
+	for _, request := range requests {
+		var response events.APIGatewayProxyResponse
+		if err := executeHandler(handler, request, &response); err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, _ := base64.StdEncoding.DecodeString(response.Body)
+		if response.StatusCode != http.StatusOK || !response.IsBase64Encoded || string(decoded) != "Random content" {
+			t.Errorf("Unexpected response %v", response)
+		}
+	}
+}
+
 func TestAPIGatewayRequestResponse(t *testing.T) {
 	cases := []struct {
 		scenario               string
@@ -138,7 +167,7 @@ func TestAPIGatewayRequestResponse(t *testing.T) {
 				interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
 				interceptors.ValidateBodyJSONSchema(schema),
 				interceptors.ParseBody(&Input{}, false)),
-			expectedBody:   `/: {"content":"Random c... "value" value is required`,
+			expectedBody:   `{"errors":[{"field":"/","message":"\"value\" value is required"}]}`,
 			expectedStatus: http.StatusUnprocessableEntity,
 		},
 		{
@@ -148,7 +177,7 @@ func TestAPIGatewayRequestResponse(t *testing.T) {
 				interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
 				interceptors.ValidateBodyJSONSchema(schema),
 				interceptors.ParseBody(&Input{}, false)),
-			expectedBody:   `/value: "30" type should be integer, got string`,
+			expectedBody:   `{"errors":[{"field":"/value","message":"type should be integer, got string"}]}`,
 			expectedStatus: http.StatusUnprocessableEntity,
 		},
 		{
@@ -158,7 +187,7 @@ func TestAPIGatewayRequestResponse(t *testing.T) {
 				interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
 				interceptors.ValidateBodyJSONSchema(schema),
 				interceptors.ParseBody(&Input{}, false)),
-			expectedBody:   `/value: 20 must be less than or equal to 2.000000`,
+			expectedBody:   `{"errors":[{"field":"/value","message":"must be less than or equal to 2.000000"}]}`,
 			expectedStatus: http.StatusUnprocessableEntity,
 		},
 		{
@@ -277,6 +306,62 @@ func TestAPIGatewayRequestResponse(t *testing.T) {
 	}
 }
 
+func TestParseBodyInput(t *testing.T) {
+	input := &Input{}
+	handler := gointercept.This(func(input Input) (Input, error) {
+		return input, nil
+	}).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+		interceptors.ParseBody(input, false))
+
+	if _, err := handler(context.TODO(), events.APIGatewayProxyRequest{Body: `{"content": "first", "value": 3}`}); err != nil {
+		t.Fatal(err)
+	}
+
+	var response events.APIGatewayProxyResponse
+	if err := executeHandler(handler, events.APIGatewayProxyRequest{Body: `{"content": "second"}`}, &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Body != `{"content":"second","value":0}` || input.Content != "" {
+		t.Errorf("Unexpected response %s and input %v", response.Body, input)
+	}
+
+	handler = gointercept.This(simpleFunction).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+		interceptors.ParseBody(Input{}, false))
+	if err := executeHandler(handler, events.APIGatewayProxyRequest{Body: `{"content": "first"}`}, &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusInternalServerError || response.Body != "ParseBody needs a non-nil pointer, got tests.Input" {
+		t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+	}
+}
+
+func TestNewBodyJSONSchemaValidator(t *testing.T) {
+	if _, err := interceptors.NewBodyJSONSchemaValidator(`{"type": 5}`); err == nil {
+		t.Error("Expected an error for an invalid schema")
+	}
+
+	validator, err := interceptors.NewBodyJSONSchemaValidator(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := gointercept.This(simpleFunction).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+		validator,
+		interceptors.ParseBody(&Input{}, true))
+
+	var response events.APIGatewayProxyResponse
+	if err := executeHandler(handler, events.APIGatewayProxyRequest{Body: `{ "value": 20, "extra": true }`}, &response); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedBody := `{"errors":[{"field":"/","message":"\"content\" value is required"},{"field":"/value","message":"must be less than or equal to 2.000000"}]}`
+	if response.StatusCode != http.StatusUnprocessableEntity || response.Body != expectedBody {
+		t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+	}
+}
+
 func executeHandler(handler gointercept.LambdaHandler, request interface{}, response interface{}) error {
 	resp, err := handler(context.TODO(), request)
 	if err != nil {
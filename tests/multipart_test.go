@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"mime/multipart"
+	"net/http"
+	"testing"
+)
+
+func multipartRequest(t *testing.T, fileContent string) events.APIGatewayProxyRequest {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("title", "Holidays"); err != nil {
+		t.Fatal(err)
+	}
+	file, err := writer.CreateFormFile("photo", "beach.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Write([]byte(fileContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return events.APIGatewayProxyRequest{
+		Headers:         map[string]string{"Content-Type": writer.FormDataContentType()},
+		Body:            base64.StdEncoding.EncodeToString(body.Bytes()),
+		IsBase64Encoded: true,
+	}
+}
+
+func TestParseMultipartForm(t *testing.T) {
+	handler := gointercept.This(func(ctx context.Context, form interceptors.MultipartForm) (string, error) {
+		photo, _ := form.File("photo")
+		fromContext, _ := interceptors.GetMultipartForm(ctx)
+		return fmt.Sprintf("%s:%s:%s:%d", form.Value("title"), photo.Filename, photo.Content, len(fromContext.Files)), nil
+	}).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+		interceptors.ParseMultipartForm(16, 1024))
+
+	cases := []struct {
+		scenario       string
+		request        events.APIGatewayProxyRequest
+		expectedBody   string
+		expectedStatus int
+	}{
+		{"Fields and files", multipartRequest(t, "PNG data"), `"Holidays:beach.png:PNG data:1"`, http.StatusOK},
+		{"Part too large", multipartRequest(t, "PNG data that is too large"), `part "photo" exceeds 16 bytes`, http.StatusRequestEntityTooLarge},
+		{"Body too large", multipartRequest(t, string(make([]byte, 2048))), `request body exceeds 1024 bytes`, http.StatusRequestEntityTooLarge},
+		{"Missing boundary", events.APIGatewayProxyRequest{Headers: map[string]string{"content-type": "multipart/form-data"}, Body: "--"},
+			`multipart boundary not found`, http.StatusBadRequest},
+		{"Not multipart", events.APIGatewayProxyRequest{Headers: map[string]string{"content-type": "application/json"}, Body: "{}"},
+			`unsupported Content-Type "application/json" - expected multipart/form-data`, http.StatusUnsupportedMediaType},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			var response events.APIGatewayProxyResponse
+			if err := executeHandler(handler, c.request, &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != c.expectedStatus || response.Body != c.expectedBody {
+				t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+			}
+		})
+	}
+}
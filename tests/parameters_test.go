@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type ListOrders struct {
+	CustomerID int        `path:"customerId"`
+	Limit      int        `query:"limit" default:"10"`
+	Status     []string   `query:"status" default:"open,shipped"`
+	Since      *time.Time `query:"since"`
+	Tenant     string     `header:"x-tenant"`
+}
+
+func TestBindParameters(t *testing.T) {
+	handler := gointercept.This(interceptors.Router(interceptors.Route{
+		Method: http.MethodGet,
+		Path:   "/customers/{customerId}/orders",
+		Handler: gointercept.This(func(ctx context.Context) (string, error) {
+			p := interceptors.GetParameters(ctx).(*ListOrders)
+			return fmt.Sprintf("%d %d %v %v %s", p.CustomerID, p.Limit, p.Status, p.Since != nil, p.Tenant), nil
+		}).With(interceptors.BindParameters(&ListOrders{})),
+	})).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusInternalServerError}))
+
+	cases := []struct {
+		scenario       string
+		request        events.APIGatewayProxyRequest
+		expectedBody   string
+		expectedStatus int
+	}{
+		{"All parameters", events.APIGatewayProxyRequest{
+			HTTPMethod:                      http.MethodGet,
+			Path:                            "/customers/42/orders",
+			QueryStringParameters:           map[string]string{"limit": "5", "since": "2020-01-01T00:00:00Z"},
+			MultiValueQueryStringParameters: map[string][]string{"status": {"open", "cancelled"}},
+			Headers:                         map[string]string{"X-Tenant": "acme"},
+		}, `"42 5 [open cancelled] true acme"`, http.StatusOK},
+		{"Defaults", events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodGet,
+			Path:       "/customers/42/orders",
+		}, `"42 10 [open shipped] false "`, http.StatusOK},
+		{"Invalid path parameter", events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodGet,
+			Path:       "/customers/abc/orders",
+		}, `invalid path parameter customerId - strconv.ParseInt: parsing "abc": invalid syntax`, http.StatusBadRequest},
+		{"Invalid query parameter", events.APIGatewayProxyRequest{
+			HTTPMethod:            http.MethodGet,
+			Path:                  "/customers/42/orders",
+			QueryStringParameters: map[string]string{"since": "yesterday"},
+		}, `invalid query parameter since - parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`, http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			var response events.APIGatewayProxyResponse
+			if err := executeHandler(handler, c.request, &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != c.expectedStatus || response.Body != c.expectedBody {
+				t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+			}
+		})
+	}
+}
+
+func TestBindParametersInvalidInput(t *testing.T) {
+	cases := []struct {
+		scenario     string
+		input        interface{}
+		expectedBody string
+	}{
+		{"Nil input", nil, "BindParameters needs a non-nil pointer to a struct, got <nil>"},
+		{"Nil pointer", (*ListOrders)(nil), "BindParameters needs a non-nil pointer to a struct, got *tests.ListOrders"},
+		{"Pointer to a non-struct", new(int), "BindParameters needs a non-nil pointer to a struct, got *int"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			handler := gointercept.This(simpleFunction).With(
+				interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+				interceptors.BindParameters(c.input))
+
+			var response events.APIGatewayProxyResponse
+			if err := executeHandler(handler, events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/"}, &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != http.StatusInternalServerError || response.Body != c.expectedBody {
+				t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+			}
+		})
+	}
+}
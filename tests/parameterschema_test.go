@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"testing"
+)
+
+func TestNewParametersJSONSchemaValidator(t *testing.T) {
+	if _, err := interceptors.NewParametersJSONSchemaValidator(interceptors.ParameterSchemas{Query: `{"type": 5}`}); err == nil {
+		t.Error("Expected an error for an invalid schema")
+	}
+
+	validator, err := interceptors.NewParametersJSONSchemaValidator(interceptors.ParameterSchemas{
+		Path: `{"type": "object", "properties": {"id": {"type": "integer", "minimum": 1}}, "required": ["id"]}`,
+		Query: `{
+			"type": "object",
+			"properties": {
+				"limit": {"type": "integer", "maximum": 100},
+				"expand": {"type": "boolean"},
+				"status": {"type": "array", "items": {"enum": ["open", "shipped"]}}
+			},
+			"additionalProperties": false
+		}`,
+		Headers: `{"type": "object", "properties": {"X-Tenant": {"type": "string", "maxLength": 4}}, "required": ["X-Tenant"]}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := gointercept.This(func() (string, error) {
+		return "valid", nil
+	}).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusInternalServerError}),
+		validator)
+
+	cases := []struct {
+		scenario       string
+		request        events.APIGatewayProxyRequest
+		expectedBody   string
+		expectedStatus int
+	}{
+		{"Valid parameters", events.APIGatewayProxyRequest{
+			PathParameters:                  map[string]string{"id": "42"},
+			QueryStringParameters:           map[string]string{"limit": "10", "expand": "true"},
+			MultiValueQueryStringParameters: map[string][]string{"status": {"open", "shipped"}},
+			Headers:                         map[string]string{"x-tenant": "acme", "User-Agent": "test"},
+		}, `"valid"`, http.StatusOK},
+		{"Invalid parameters", events.APIGatewayProxyRequest{
+			PathParameters:        map[string]string{"id": "abc"},
+			QueryStringParameters: map[string]string{"limit": "500", "color": "red"},
+			Headers:               map[string]string{"X-Tenant": "acme-corp"},
+		}, `{"errors":[{"field":"/path/id","message":"type should be integer, got string"},` +
+			`{"field":"/query/limit","message":"must be less than or equal to 100.000000"},` +
+			`{"field":"/query","message":"additional properties are not allowed"},` +
+			`{"field":"/headers/X-Tenant","message":"max length of 4 characters exceeded: acme-corp"}]}`, http.StatusBadRequest},
+		{"Missing parameters", events.APIGatewayProxyRequest{}, `{"errors":[{"field":"/path","message":"\"id\" value is required"},` +
+			`{"field":"/headers","message":"\"X-Tenant\" value is required"}]}`, http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			var response events.APIGatewayProxyResponse
+			if err := executeHandler(handler, c.request, &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != c.expectedStatus || response.Body != c.expectedBody {
+				t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"testing"
+)
+
+const outputSchema = `{
+	"type": "object",
+	"properties": {
+		"Status": {"type": "string"},
+		"Content": {"type": "string", "maxLength": 5}
+	},
+	"required": ["Status", "Content"],
+	"additionalProperties": false
+}`
+
+func TestNewResponseJSONSchemaValidator(t *testing.T) {
+	if _, err := interceptors.NewResponseJSONSchemaValidator(`{"type": 5}`, interceptors.LogResponseViolations); err == nil {
+		t.Error("Expected an error for an invalid schema")
+	}
+
+	logOnly, err := interceptors.NewResponseJSONSchemaValidator(outputSchema, interceptors.LogResponseViolations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fail, err := interceptors.NewResponseJSONSchemaValidator(outputSchema, interceptors.FailOnResponseViolations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		scenario       string
+		handler        gointercept.LambdaHandler
+		body           string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{"Valid response", gointercept.This(simpleFunction).With(
+			interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+			fail,
+			interceptors.ParseBody(&Input{}, false)),
+			`{"content": "short", "value": 2}`, `{"Status":"Function ran successfully!","Content":"short"}`, http.StatusOK},
+		{"Invalid response (log only)", gointercept.This(simpleFunction).With(
+			interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+			logOnly,
+			interceptors.ParseBody(&Input{}, false)),
+			`{"content": "Random content", "value": 2}`, `{"Status":"Function ran successfully!","Content":"Random content"}`, http.StatusOK},
+		{"Invalid response (fail)", gointercept.This(simpleFunction).With(
+			interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+			fail,
+			interceptors.ParseBody(&Input{}, false)),
+			`{"content": "Random content", "value": 2}`, `Internal Server Error`, http.StatusInternalServerError},
+		{"Invalid API Gateway response body", gointercept.This(simpleFunction).With(
+			fail,
+			interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+			interceptors.ParseBody(&Input{}, false)),
+			`{"content": "Random content", "value": 2}`, "", 0},
+		{"Error responses are not validated", gointercept.This(simpleFunction).With(
+			fail,
+			interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+			interceptors.ParseBody(&Input{}, false)),
+			`{"content": "Random content", "value": 1}`, `Value is not even`, http.StatusUnprocessableEntity},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			response, err := c.handler(context.TODO(), events.APIGatewayProxyRequest{Body: c.body})
+			if c.expectedStatus == 0 {
+				var validationError *interceptors.ValidationError
+				if !errors.As(err, &validationError) || validationError.Violations[0].Field != "/Content" {
+					t.Errorf("Expected a ValidationError, got %v", err)
+				}
+				return
+			}
+
+			apiGatewayResponse, ok := response.(events.APIGatewayProxyResponse)
+			if err != nil || !ok || apiGatewayResponse.StatusCode != c.expectedStatus || apiGatewayResponse.Body != c.expectedBody {
+				t.Errorf("Unexpected response %v %v", response, err)
+			}
+		})
+	}
+}
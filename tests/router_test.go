@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"testing"
+)
+
+func getItem(ctx context.Context, request events.APIGatewayProxyRequest) (map[string]string, error) {
+	return map[string]string{
+		"id":    request.PathParameters["id"],
+		"proxy": interceptors.PathParameters(ctx)["proxy"],
+	}, nil
+}
+
+func TestRouter(t *testing.T) {
+	handler := gointercept.This(interceptors.Router(
+		interceptors.Route{Method: http.MethodGet, Path: "/items/{id}", Handler: gointercept.This(getItem).With()},
+		interceptors.Route{Method: http.MethodDelete, Path: "/items/{id}", Handler: gointercept.This(getItem).With()},
+		interceptors.Route{Method: http.MethodGet, Path: "/items/{sku}", Handler: gointercept.This(getItem).With()},
+		interceptors.Route{Method: http.MethodPost, Path: "/items",
+			Handler: gointercept.This(simpleFunction).With(interceptors.ParseBody(&Input{}, false))},
+	)).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+	)
+
+	cases := []struct {
+		scenario        string
+		request         events.APIGatewayProxyRequest
+		expectedBody    string
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		{
+			scenario:       "Path parameters",
+			request:        events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/items/42", PathParameters: map[string]string{"proxy": "items/42"}},
+			expectedBody:   `{"id":"42","proxy":"items/42"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			scenario:       "Route with its own interceptors",
+			request:        events.APIGatewayProxyRequest{HTTPMethod: http.MethodPost, Path: "/items/", Body: `{"content": "Random content", "value": 2 }`},
+			expectedBody:   `{"Status":"Function ran successfully!","Content":"Random content"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			scenario:       "Route not found",
+			request:        events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/users/42"},
+			expectedBody:   `/users/42 not found`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			scenario:        "Method not allowed",
+			request:         events.APIGatewayProxyRequest{HTTPMethod: http.MethodPut, Path: "/items/42"},
+			expectedBody:    `method PUT not allowed for /items/42`,
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedHeaders: map[string]string{"Allow": "DELETE, GET"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			var response events.APIGatewayProxyResponse
+			if err := executeHandler(handler, c.request, &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if response.Body != c.expectedBody {
+				t.Errorf("Unexpected content '%s' in response's body", response.Body)
+			}
+			if response.StatusCode != c.expectedStatus {
+				t.Errorf("Unexpected status '%d' in response", response.StatusCode)
+			}
+			for key, value := range c.expectedHeaders {
+				if response.Headers[key] != value {
+					t.Errorf("Expected header '%s: %s' in response not found", key, value)
+				}
+			}
+		})
+	}
+}
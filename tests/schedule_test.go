@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"testing"
+	"time"
+)
+
+func scheduledEvent(id string) events.CloudWatchEvent {
+	return events.CloudWatchEvent{
+		ID:         id,
+		Source:     "aws.events",
+		DetailType: "Scheduled Event",
+		Time:       time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestHandleScheduledEvent(t *testing.T) {
+	runs := 0
+	fail := false
+	handler := gointercept.This(func(ctx context.Context) (string, error) {
+		runs++
+		if fail {
+			return "", errors.New("job failed")
+		}
+		scheduledTime, _ := interceptors.ScheduledTime(ctx)
+		return scheduledTime.Format(time.RFC3339), nil
+	}).With(interceptors.HandleScheduledEvent(&interceptors.MemoryDeduplicationStore{TTL: time.Hour}))
+
+	response, err := handler(context.TODO(), scheduledEvent("1"))
+	if err != nil || response != "2020-06-01T10:00:00Z" {
+		t.Errorf("Unexpected response %v %v", response, err)
+	}
+
+	response, err = handler(context.TODO(), genericPayload(t, `{"id": "1", "source": "aws.events", "detail-type": "Scheduled Event"}`))
+	if err != nil || response != nil || runs != 1 {
+		t.Errorf("Expected duplicate event to be skipped, got %v %v", response, err)
+	}
+
+	fail = true
+	if _, err = handler(context.TODO(), scheduledEvent("2")); err == nil {
+		t.Error("Expected the job to fail")
+	}
+	fail = false
+	if _, err = handler(context.TODO(), scheduledEvent("2")); err != nil || runs != 3 {
+		t.Errorf("Expected failed event to be retried, got %v", err)
+	}
+
+	_, err = handler(context.TODO(), events.CloudWatchEvent{ID: "3", Source: "aws.s3", DetailType: "Object Created"})
+	if !errors.Is(err, interceptors.ErrNotScheduledEvent) {
+		t.Errorf("Expected ErrNotScheduledEvent, got %v", err)
+	}
+}
+
+func TestScheduledEventDetail(t *testing.T) {
+	type jobInput struct {
+		Job   string `json:"job"`
+		Limit int    `json:"limit"`
+	}
+
+	handler := gointercept.This(func(ctx context.Context) (jobInput, error) {
+		return interceptors.ScheduledEventDetail[jobInput](ctx)
+	}).With(interceptors.HandleScheduledEvent(nil))
+
+	event := scheduledEvent("1")
+	event.Detail = []byte(`{"job": "cleanup", "limit": 10}`)
+	response, err := handler(context.TODO(), event)
+	if err != nil || response != (jobInput{Job: "cleanup", Limit: 10}) {
+		t.Errorf("Unexpected response %v %v", response, err)
+	}
+
+	event.Detail = []byte(`{"limit": "ten"}`)
+	if _, err := handler(context.TODO(), event); err == nil {
+		t.Error("Expected an error for an invalid detail")
+	}
+
+	if _, err := interceptors.ScheduledEventDetail[jobInput](context.TODO()); !errors.Is(err, interceptors.ErrNotScheduledEvent) {
+		t.Errorf("Expected ErrNotScheduledEvent, got %v", err)
+	}
+}
+
+func TestMemoryDeduplicationStoreTTL(t *testing.T) {
+	if _, err := (&interceptors.MemoryDeduplicationStore{}).Claim(context.TODO(), "1"); err == nil {
+		t.Error("Expected an error for a store without TTL")
+	}
+
+	store := &interceptors.MemoryDeduplicationStore{TTL: time.Millisecond}
+	if claimed, err := store.Claim(context.TODO(), "1"); err != nil || !claimed {
+		t.Fatalf("Expected the event to be claimed, got %v %v", claimed, err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if claimed, err := store.Claim(context.TODO(), "1"); err != nil || !claimed {
+		t.Errorf("Expected the expired event to be claimed again, got %v %v", claimed, err)
+	}
+}
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type Category struct {
+	Name     string     `json:"name" validate:"required,max=10"`
+	Children []Category `json:"children,omitempty" validate:"max=2"`
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	cases := []struct {
+		scenario string
+		input    interface{}
+		expected string
+	}{
+		{"Plain struct", &Input{}, `{"properties":{"content":{"type":"string"},"value":{"type":"integer"}},"type":"object"}`},
+		{"Recursive type", &Category{}, `{"properties":{"children":{"items":{"$ref":"#"},"maxItems":2,"type":"array"},` +
+			`"name":{"maxLength":10,"minLength":1,"type":"string"}},"required":["name"],"type":"object"}`},
+		{"Nested types", &Customer{}, `{"$defs":{"Address":{"properties":{"city":{"minLength":1,"type":"string"},"country":{"maxLength":2,"minLength":2,"type":"string"}},` +
+			`"required":["city"],"type":"object"},` +
+			`"Item":{"properties":{"quantity":{"maximum":10,"minimum":1,"type":"integer"},"sku":{"minLength":1,"pattern":"^[A-Z]{3}-[0-9]+$","type":"string"}},` +
+			`"required":["sku"],"type":"object"}},` +
+			`"properties":{"address":{"$ref":"#/$defs/Address"},"email":{"format":"email","minLength":1,"type":"string"},` +
+			`"id":{"format":"uuid","minLength":1,"pattern":"^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$","type":"string"},` +
+			`"items":{"items":{"$ref":"#/$defs/Item"},"maxItems":2,"minItems":1,"type":"array"},"name":{"maxLength":5,"minLength":2,"type":"string"},` +
+			`"tier":{"enum":["gold","silver"],"type":"string"}},"required":["id","email","address","items"],"type":"object"}`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			schema, err := interceptors.GenerateJSONSchema(c.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if schema != c.expected {
+				t.Errorf("Unexpected schema %s", schema)
+			}
+		})
+	}
+
+	if _, err := interceptors.GenerateJSONSchema(struct {
+		Values map[int]string
+	}{}); err == nil {
+		t.Error("Expected an error for a map with non-string keys")
+	}
+}
+
+func TestNewBodyJSONSchemaValidatorFor(t *testing.T) {
+	validator, err := interceptors.NewBodyJSONSchemaValidatorFor(&Category{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := gointercept.This(func(category Category) (string, error) {
+		return category.Name, nil
+	}).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+		validator,
+		interceptors.ParseBody(&Category{}, false))
+
+	cases := []struct {
+		scenario       string
+		body           string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{"Valid input", `{"name": "Books", "children": [{"name": "Fiction"}]}`, `"Books"`, http.StatusOK},
+		{"Invalid nested input", `{"name": "Books", "children": [{"name": "Science Fiction"}]}`,
+			`{"errors":[{"field":"/children/0/name","message":"max length of 10 characters exceeded: Science Fiction"}]}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			var response events.APIGatewayProxyResponse
+			if err := executeHandler(handler, events.APIGatewayProxyRequest{Body: c.body}, &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != c.expectedStatus || response.Body != c.expectedBody {
+				t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+			}
+		})
+	}
+}
+
+type Profile struct {
+	Name     string   `json:"name" validate:"required"`
+	Age      int      `json:"age" validate:"required"`
+	Score    int      `json:"score" validate:"min=1,max=5"`
+	Tags     []string `json:"tags" validate:"required"`
+	Active   bool     `json:"active" validate:"required"`
+	Tier     string   `json:"tier" validate:"enum=gold|silver"`
+	Nickname *string  `json:"nickname" validate:"min=2"`
+	Level    *string  `json:"level" validate:"enum=a|b"`
+}
+
+func TestGeneratedSchemaMatchesValidateStruct(t *testing.T) {
+	schemaValidator, err := interceptors.NewBodyJSONSchemaValidatorFor(&Profile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	validators := map[string]gointercept.LambdaHandler{
+		"schema": gointercept.This(func(profile Profile) error {
+			return nil
+		}).With(schemaValidator),
+		"struct": gointercept.This(func(profile Profile) error {
+			return nil
+		}).With(interceptors.ParseBody(&Profile{}, false), interceptors.ValidateStruct()),
+	}
+
+	cases := []struct {
+		scenario       string
+		body           string
+		expectedFields []string
+	}{
+		{"Valid input", `{"name": "Jo", "age": 3, "score": 2, "tags": ["a"], "active": true, "tier": "gold", "nickname": null, "level": null}`, nil},
+		{"Zero values", `{"name": "", "age": 0, "score": 0, "tags": [], "active": false, "tier": "", "nickname": "x", "level": "c"}`,
+			[]string{"active", "age", "level", "name", "nickname", "score", "tags", "tier"}},
+	}
+
+	for name, validator := range validators {
+		for _, c := range cases {
+			t.Run(name+"/"+c.scenario, func(t *testing.T) {
+				_, err := validator(context.TODO(), events.APIGatewayProxyRequest{Body: c.body})
+				var fields []string
+				var validationError *interceptors.ValidationError
+				if errors.As(err, &validationError) {
+					invalid := make(map[string]bool)
+					for _, violation := range validationError.Violations {
+						invalid[strings.TrimPrefix(violation.Field, "/")] = true
+					}
+					for field := range invalid {
+						fields = append(fields, field)
+					}
+					sort.Strings(fields)
+				} else if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(fields, c.expectedFields) {
+					t.Errorf("Unexpected invalid fields %v", fields)
+				}
+			})
+		}
+	}
+}
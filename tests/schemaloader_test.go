@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"embed"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"testing"
+	"testing/fstest"
+)
+
+//go:embed testdata/schemas
+var schemas embed.FS
+
+func TestLoadJSONSchema(t *testing.T) {
+	registry, err := interceptors.LoadSchemaRegistry(schemas, "testdata/schemas/*/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"order.json", "customer.json"} {
+		b, err := schemas.ReadFile("testdata/schemas/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := registry.Add(string(b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fromFS, err := interceptors.NewBodyJSONSchemaValidatorFromFS(schemas, "testdata/schemas/order.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromRegistry, err := interceptors.NewBodyJSONSchemaValidatorFromRegistry(registry, "https://example.com/schemas/order.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		scenario       string
+		body           string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{"Valid input", `{"customer": {"name": "Jane", "address": {"city": "Lima"}}, "items": [{"sku": "A", "quantity": 1}]}`,
+			`"valid"`, http.StatusOK},
+		{"Invalid referenced file", `{"customer": {"name": "Jane", "address": {"zip": "12345"}}, "items": [{"sku": "A", "quantity": 1}]}`,
+			`{"errors":[{"field":"/customer/address","message":"\"city\" value is required"}]}`, http.StatusUnprocessableEntity},
+		{"Invalid nested directory file", `{"customer": {"name": "Jane"}, "shipping": {"city": "Lima", "zip": "1"}, "items": [{"sku": "A", "quantity": 1}]}`,
+			`{"errors":[{"field":"/shipping/zip","message":"regexp pattern ^[0-9]{5}$ mismatch on string: 1"}]}`, http.StatusUnprocessableEntity},
+		{"Invalid fragment of a referenced file", `{"customer": {"name": "Jane"}, "items": [{"sku": "A", "quantity": "x"}]}`,
+			`{"errors":[{"field":"/items/0/quantity","message":"type should be integer, got string"}]}`, http.StatusUnprocessableEntity},
+	}
+
+	validators := []struct {
+		name      string
+		validator gointercept.Interceptor
+	}{
+		{"fs", fromFS},
+		{"registry", fromRegistry},
+	}
+
+	for _, v := range validators {
+		handler := gointercept.This(func() (string, error) {
+			return "valid", nil
+		}).With(
+			interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+			v.validator)
+
+		for _, c := range cases {
+			t.Run(v.name+"/"+c.scenario, func(t *testing.T) {
+				var response events.APIGatewayProxyResponse
+				if err := executeHandler(handler, events.APIGatewayProxyRequest{Body: c.body}, &response); err != nil {
+					t.Fatal(err)
+				}
+				if response.StatusCode != c.expectedStatus || response.Body != c.expectedBody {
+					t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+				}
+			})
+		}
+	}
+}
+
+func TestLoadJSONSchemaBundle(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root.json": {Data: []byte(`{
+			"properties": {
+				"enum": {"$ref": "a/address.json"},
+				"const": {"$ref": "b/address.json"},
+				"default": {"$ref": "b/address.json"}
+			},
+			"default": {"$ref": "unknown.json"}
+		}`)},
+		"a/address.json": {Data: []byte(`{"type": "string"}`)},
+		"b/address.json": {Data: []byte(`{"type": "integer"}`)},
+	}
+
+	expected := `{"$defs":{"address":{"type":"integer"},"address_2":{"type":"string"}},"default":{"$ref":"unknown.json"},` +
+		`"properties":{"const":{"$ref":"#/$defs/address"},"default":{"$ref":"#/$defs/address"},"enum":{"$ref":"#/$defs/address_2"}}}`
+	for i := 0; i < 10; i++ {
+		schema, err := interceptors.LoadJSONSchema(fsys, "root.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if schema != expected {
+			t.Fatalf("Unexpected schema %s", schema)
+		}
+	}
+}
+
+func TestLoadJSONSchemaErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"missing.json": {Data: []byte(`{"properties": {"a": {"$ref": "other.json"}}}`)},
+		"remote.json":  {Data: []byte(`{"properties": {"a": {"$ref": "https://example.com/a.json"}}}`)},
+		"anchor.json":  {Data: []byte(`{"properties": {"a": {"$ref": "#anchor"}}}`)},
+	}
+
+	for _, name := range []string{"missing.json", "remote.json", "anchor.json", "unknown.json"} {
+		if _, err := interceptors.LoadJSONSchema(fsys, name); err == nil {
+			t.Errorf("Expected an error loading %s", name)
+		}
+	}
+
+	if _, err := interceptors.NewSchemaRegistry(`{"type": "object"}`); err == nil {
+		t.Error("Expected an error for a schema without $id")
+	}
+	registry, err := interceptors.NewSchemaRegistry(`{"$id": "https://example.com/a.json", "$ref": "b.json"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.Schema("https://example.com/a.json"); err == nil {
+		t.Error("Expected an error for an unknown reference")
+	}
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"testing"
+)
+
+var errOutOfStock = errors.New("out of stock")
+
+func TestMapErrorTypes(t *testing.T) {
+	cases := []struct {
+		scenario     string
+		err          error
+		expectedType string
+	}{
+		{"Wrapped sentinel", fmt.Errorf("can't reserve item: %w", errOutOfStock), "OutOfStock"},
+		{"HTTP error", &interceptors.HTTPError{StatusCode: http.StatusTooManyRequests, StatusText: "slow down"}, "TooManyRequests"},
+		{"Unmapped error", errors.New("boom"), ""},
+	}
+
+	t.Run("Sentinel wrapped by ParseBody", func(t *testing.T) {
+		handler := gointercept.This(func(input Input) error { return errOutOfStock }).With(
+			interceptors.MapErrorTypes(interceptors.ErrorTypeMapping{Target: errOutOfStock, Type: "OutOfStock"}),
+			interceptors.ParseBody(&Input{}, false),
+		)
+
+		_, err := handler(context.TODO(), genericPayload(t, `{"body": "{\"content\": \"foo\"}"}`))
+		if invokeError, ok := err.(messages.InvokeResponse_Error); !ok || invokeError.Type != "OutOfStock" {
+			t.Errorf("Unexpected error %v", err)
+		}
+	})
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			handler := gointercept.This(func() error { return c.err }).With(
+				interceptors.MapErrorTypes(interceptors.ErrorTypeMapping{Target: errOutOfStock, Type: "OutOfStock"}),
+			)
+
+			_, err := handler(context.TODO(), nil)
+			invokeError, ok := err.(messages.InvokeResponse_Error)
+			if c.expectedType == "" {
+				if ok || err != c.err {
+					t.Errorf("Expected the error to be unchanged, got %v", err)
+				}
+				return
+			}
+			if !ok || invokeError.Type != c.expectedType {
+				t.Errorf("Unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestProjectPaths(t *testing.T) {
+	handler := gointercept.This(func(input Input) (map[string]interface{}, error) {
+		return map[string]interface{}{"result": map[string]interface{}{"content": input.Content}, "debug": true}, nil
+	}).With(interceptors.ProjectPaths("$.detail.items[1]", "$.result"))
+
+	request := genericPayload(t, `{"detail": {"items": [{"content": "first"}, {"content": "second", "value": 2}]}}`)
+
+	var response map[string]string
+	if err := executeHandler(handler, request, &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response) != 1 || response["content"] != "second" {
+		t.Errorf("Unexpected response %v", response)
+	}
+
+	if _, err := gointercept.This(simpleFunction).With(interceptors.ProjectPaths("$.missing", ""))(context.TODO(), request); err == nil {
+		t.Errorf("Expected an error for a missing field")
+	}
+	if _, err := gointercept.This(simpleFunction).With(interceptors.ProjectPaths("detail", ""))(context.TODO(), request); err == nil {
+		t.Errorf("Expected an error for an invalid path")
+	}
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"testing"
+)
+
+type Address struct {
+	City    string `json:"city" validate:"required"`
+	Country string `json:"country" validate:"len=2"`
+}
+
+type Item struct {
+	SKU      string `json:"sku" validate:"required" pattern:"^[A-Z]{3}-[0-9]+$"`
+	Quantity int    `json:"quantity" validate:"min=1,max=10"`
+}
+
+type Customer struct {
+	ID       string   `json:"id" validate:"required,uuid"`
+	Email    string   `json:"email" validate:"required,email"`
+	Name     string   `json:"name" validate:"min=2,max=5"`
+	Tier     string   `json:"tier" validate:"enum=gold|silver"`
+	Address  *Address `json:"address" validate:"required"`
+	Items    []Item   `json:"items" validate:"required,max=2"`
+	Internal string   `json:"-" validate:"required"`
+}
+
+func TestValidateStruct(t *testing.T) {
+	handler := gointercept.This(func(customer Customer) (string, error) {
+		return customer.Name, nil
+	}).With(
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+		interceptors.ParseBody(&Customer{}, false),
+		interceptors.ValidateStruct())
+
+	cases := []struct {
+		scenario       string
+		body           string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{
+			scenario: "Valid input",
+			body: `{"id": "0f8fad5b-d9cb-469f-a165-70867728950e", "email": "jane@example.com", "name": "Jane", "tier": "gold",
+				"address": {"city": "Lima", "country": "PE"}, "items": [{"sku": "ABC-1", "quantity": 2}]}`,
+			expectedBody:   `"Jane"`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			scenario: "All violations",
+			body: `{"id": "123", "email": "Jane <jane@example.com>", "name": "Jane Doe", "tier": "bronze",
+				"address": {"country": "Peru"}, "items": [{"sku": "abc", "quantity": 0}, {"sku": "ABC-2", "quantity": 20}]}`,
+			expectedBody: `{"errors":[{"field":"id","message":"must be a valid UUID"},` +
+				`{"field":"email","message":"must be a valid email address"},` +
+				`{"field":"name","message":"length must be at most 5"},` +
+				`{"field":"tier","message":"must be one of gold, silver"},` +
+				`{"field":"address.city","message":"is required"},` +
+				`{"field":"address.country","message":"length must be 2"},` +
+				`{"field":"items[0].sku","message":"must match the pattern ^[A-Z]{3}-[0-9]+$"},` +
+				`{"field":"items[0].quantity","message":"value must be at least 1"},` +
+				`{"field":"items[1].quantity","message":"value must be at most 10"}]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			scenario: "Missing fields",
+			body:     `{"name": "Jane"}`,
+			expectedBody: `{"errors":[{"field":"id","message":"is required"},{"field":"email","message":"is required"},` +
+				`{"field":"tier","message":"must be one of gold, silver"},` +
+				`{"field":"address","message":"is required"},{"field":"items","message":"is required"}]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			var response events.APIGatewayProxyResponse
+			if err := executeHandler(handler, events.APIGatewayProxyRequest{Body: c.body}, &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != c.expectedStatus || response.Body != c.expectedBody {
+				t.Errorf("Unexpected response %d %s", response.StatusCode, response.Body)
+			}
+		})
+	}
+}
+
+func TestValidateStructError(t *testing.T) {
+	handler := gointercept.This(func(item Item) error {
+		return nil
+	}).With(interceptors.ParseBody(&Item{}, false), interceptors.ValidateStruct())
+
+	_, err := handler(context.TODO(), events.APIGatewayProxyRequest{Body: `{"sku": "ABC-1", "quantity": 11}`})
+
+	var validationError *interceptors.ValidationError
+	if !errors.As(err, &validationError) || len(validationError.Violations) != 1 || validationError.Violations[0].Field != "quantity" {
+		t.Errorf("Expected a ValidationError, got %v", err)
+	}
+}
+
+func TestValidateStructRules(t *testing.T) {
+	type Rules struct {
+		Count    int     `json:"count" validate:"email" pattern:"^1$"`
+		Nickname *string `json:"nickname" validate:"min=2"`
+		Code     string  `json:"code" validate:"uuid"`
+	}
+
+	handler := gointercept.This(func(rules Rules) error {
+		return nil
+	}).With(interceptors.ParseBody(&Rules{}, false), interceptors.ValidateStruct())
+
+	_, err := handler(context.TODO(), events.APIGatewayProxyRequest{Body: `{"count": 1}`})
+
+	var validationError *interceptors.ValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	expected := "count: rule email can't be applied to int; count: pattern can't be applied to int; code: must be a valid UUID"
+	if validationError.Error() != expected {
+		t.Errorf("Unexpected violations %s", validationError)
+	}
+}
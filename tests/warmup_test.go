@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"testing"
+)
+
+func TestSkipWarmup(t *testing.T) {
+	initialized := 0
+	handler := gointercept.This(simpleFunction).With(
+		interceptors.SkipWarmup(
+			interceptors.WarmupRules("arn:aws:events:us-east-1:123456789012:rule/warmer"),
+			interceptors.WarmupMatcher(func(event map[string]interface{}) bool { return event["ping"] == true }),
+			interceptors.WarmupInit(func(ctx context.Context) error {
+				initialized++
+				return nil
+			}),
+			interceptors.WarmupResponse("warm")),
+		interceptors.CreateAPIGatewayProxyResponse(&interceptors.DefaultStatusCodes{Success: http.StatusOK, Error: http.StatusBadRequest}),
+		interceptors.ParseBody(&Input{}, false))
+
+	cases := []struct {
+		scenario string
+		request  interface{}
+		warmup   bool
+	}{
+		{"Warmup plugin", genericPayload(t, `{"source": "serverless-plugin-warmup"}`), true},
+		{"Warmup rule", events.CloudWatchEvent{DetailType: "Scheduled Event", Source: "aws.events", Resources: []string{"arn:aws:events:us-east-1:123456789012:rule/warmer"}}, true},
+		{"Generic warmup rule", genericPayload(t, `{"detail-type": "Scheduled Event", "source": "aws.events", "resources": ["arn:aws:events:us-east-1:123456789012:rule/warmer"]}`), true},
+		{"Custom matcher", genericPayload(t, `{"ping": true}`), true},
+		{"Typed warmup event", struct {
+			Source string `json:"source"`
+		}{Source: interceptors.WarmupPluginSource}, true},
+		{"Typed custom matcher", struct {
+			Ping bool `json:"ping"`
+		}{Ping: true}, true},
+		{"Other rule", events.CloudWatchEvent{DetailType: "Scheduled Event", Source: "aws.events", Resources: []string{"arn:aws:events:us-east-1:123456789012:rule/other"}}, false},
+		{"Regular request", events.APIGatewayProxyRequest{Body: `{"content": "Random content", "value": 2}`}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			before := initialized
+			response, err := handler(context.TODO(), c.request)
+			if c.warmup {
+				if err != nil || response != "warm" || initialized != before+1 {
+					t.Errorf("Expected warmup response, got %v %v", response, err)
+				}
+				return
+			}
+			if _, ok := response.(events.APIGatewayProxyResponse); !ok || initialized != before {
+				t.Errorf("Expected the chain to be executed, got %v %v", response, err)
+			}
+		})
+	}
+}
+
+func TestSkipWarmupInitError(t *testing.T) {
+	initError := errors.New("can't connect")
+	handler := gointercept.This(simpleFunction).With(
+		interceptors.SkipWarmup(interceptors.WarmupInit(func(ctx context.Context) error { return initError })))
+
+	_, err := handler(context.TODO(), genericPayload(t, `{"source": "serverless-plugin-warmup"}`))
+	if !errors.Is(err, initError) {
+		t.Errorf("Expected the init error, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jpcedenog/gointercept"
+	"github.com/jpcedenog/gointercept/interceptors"
+	"net/http"
+	"testing"
+)
+
+func webSocketRequest(routeKey, body string) events.APIGatewayWebsocketProxyRequest {
+	return events.APIGatewayWebsocketProxyRequest{
+		Body: body,
+		RequestContext: events.APIGatewayWebsocketProxyRequestContext{
+			RouteKey:     routeKey,
+			ConnectionID: "abc123=",
+			DomainName:   "example.execute-api.us-east-1.amazonaws.com",
+			Stage:        "prod",
+		},
+	}
+}
+
+func TestWebSocketRouter(t *testing.T) {
+	handler := gointercept.This(interceptors.WebSocketRouter(map[string]gointercept.LambdaHandler{
+		interceptors.ConnectRoute: gointercept.This(func(ctx context.Context) error {
+			if interceptors.ConnectionID(ctx) == "" {
+				return &interceptors.HTTPError{StatusCode: http.StatusUnauthorized, StatusText: "missing connection"}
+			}
+			return nil
+		}).With(),
+		"sendMessage": gointercept.This(func(ctx context.Context, input Input) (string, error) {
+			connection, _ := interceptors.GetWebSocketConnection(ctx)
+			return connection.CallbackURL() + " " + input.Content, nil
+		}).With(interceptors.ParseBody(&Input{}, false)),
+		interceptors.DefaultRoute: gointercept.This(func() error {
+			return errors.New("unsupported action")
+		}).With(),
+	})).With(interceptors.CreateWebSocketResponse())
+
+	cases := []struct {
+		scenario       string
+		request        events.APIGatewayWebsocketProxyRequest
+		expectedBody   string
+		expectedStatus int
+	}{
+		{
+			scenario:       "Connect",
+			request:        webSocketRequest(interceptors.ConnectRoute, ""),
+			expectedBody:   "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			scenario:       "Custom route with body",
+			request:        webSocketRequest("sendMessage", `{"content": "hello", "value": 2}`),
+			expectedBody:   `"https://example.execute-api.us-east-1.amazonaws.com/prod/@connections/abc123= hello"`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			scenario:       "Default route",
+			request:        webSocketRequest("unknown", ""),
+			expectedBody:   "unsupported action",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.scenario, func(t *testing.T) {
+			var response events.APIGatewayProxyResponse
+			if err := executeHandler(handler, c.request, &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if response.Body != c.expectedBody {
+				t.Errorf("Unexpected content '%s' in response's body", response.Body)
+			}
+			if response.StatusCode != c.expectedStatus {
+				t.Errorf("Unexpected status '%d' in response", response.StatusCode)
+			}
+		})
+	}
+}